@@ -1,11 +1,17 @@
 package dota2
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 //summary of steam API urls
@@ -59,6 +65,11 @@ http://api.steampowered.com/ISteamWebAPIUtil/GetSupportedAPIList/v1/
 Sample request URL with parameters
 http://api.steampowered.com/ISteamWebAPIUtil/GetSupportedAPIList/v1/?key=1234567890&steamid=000123000456
 */
+
+//URLMap lists the urls of all endpoints with the default BASE_URL.
+//
+//Deprecated: Dota2api doesn't read URLMap any more, every instance owns its endpoint table,
+//use WithBaseURL, SetEndpoint and SetEndpointVersion to change it.
 var (
 	URLMap = map[string]string{
 		"GetMatchHistory":         BASE_URL + GET_MATCH_HISTORY,
@@ -80,25 +91,28 @@ var (
 )
 
 var (
-	URLMapError = errors.New("Cannot find correspond URL in URLMap")
+	URLMapError = errors.New("Cannot find correspond endpoint")
 )
 
 type Dota2api struct {
-	apikey string
-	client *http.Client
+	apikey    string
+	client    *http.Client
+	baseURL   string
+	userAgent string
+	language  string
+	timeout   time.Duration
+	logger    Logger
+	endpoints map[string]Endpoint
+	limits    rateLimits
+	retry     RetryPolicy
+	cache     Cache
+	cacheTTLs map[string]time.Duration
 }
 
+//NewApi creates a Dota2api which sends requests with apiclient, http.DefaultClient is used when it's nil.
+//See New for more options.
 func NewApi(apiclient *http.Client) *Dota2api {
-	if apiclient == nil {
-		apiclient = http.DefaultClient
-	}
-
-	dapi := &Dota2api{
-		apikey: "",
-		client: apiclient,
-	}
-
-	return dapi
+	return New(WithHTTPClient(apiclient))
 }
 
 func (d *Dota2api) SetApiKey(apikey string) {
@@ -106,197 +120,363 @@ func (d *Dota2api) SetApiKey(apikey string) {
 }
 
 //GetMatchHistory : get recent dota2 match history of player for user's dota2 account id(not steam id).
+//A failed status is returned as *StatusError together with the result, eg: errors.Is(err, ErrPrivateProfile)
+//when the player doesn't expose the match history.
 //example:
-//	GetMatchHistory("123400001")
+//	GetMatchHistory(NewSteamID(123400001))
 //return:
 //	the detailed information of certain dota2 match.
-func (d *Dota2api) GetMatchHistory(accountid string) (MatchHistory, error) {
-	var mh MatchHistory
-	url, found := URLMap["GetMatchHistory"]
-	if !found {
-		return mh, URLMapError
-	}
+func (d *Dota2api) GetMatchHistory(account SteamID) (MatchHistory, error) {
+	return d.GetMatchHistoryContext(context.Background(), account)
+}
 
-	formurl := url + "?key=" + d.apikey + "&account_id=" + accountid
-	bmatchhistory, err := d.RequestForURL(formurl)
-	if err != nil {
-		return mh, err
+//GetMatchHistoryContext is like GetMatchHistory but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetMatchHistoryContext(ctx context.Context, account SteamID) (MatchHistory, error) {
+	var mhwrap MatchHistoryWrapper
+	account = account.canonical()
+	if !account.IsValid() {
+		return mhwrap.Result, fmt.Errorf("%w: %d", ErrInvalidAccountID, uint64(account))
 	}
 
-	var mhwrap MatchHistoryWrapper
-	err = json.Unmarshal(bmatchhistory, &mhwrap)
-	if err != nil {
-		return mh, err
+	params := url.Values{"account_id": {strconv.FormatUint(uint64(account.AccountID()), 10)}}
+	if err := d.get(ctx, "GetMatchHistory", params, &mhwrap); err != nil {
+		return mhwrap.Result, err
 	}
-	mh = mhwrap.Result
-	return mh, nil
+	return mhwrap.Result, nil
 }
 
 //GetMatchDetails will get match details by match id
 func (d *Dota2api) GetMatchDetails(matchid string) (MatchDetail, error) {
-	var mdetail MatchDetail
-	url, found := URLMap["GetMatchDetails"]
-	if !found {
-		return mdetail, URLMapError
-	}
+	return d.GetMatchDetailsContext(context.Background(), matchid)
+}
 
-	formurl := url + "?key=" + d.apikey + "&match_id=" + matchid
-	bmatchdetail, err := d.RequestForURL(formurl)
-	if err != nil {
-		return mdetail, err
+//GetMatchDetailsContext is like GetMatchDetails but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetMatchDetailsContext(ctx context.Context, matchid string) (MatchDetail, error) {
+	var mdetailwrp MatchDetailWrapper
+	if err := validateID(matchid, ErrInvalidMatchID); err != nil {
+		return mdetailwrp.Result, err
 	}
 
-	var mdetailwrp MatchDetailWrapper
-	err = json.Unmarshal(bmatchdetail, &mdetailwrp)
-	if err != nil {
-		return mdetail, err
+	params := url.Values{"match_id": {matchid}}
+	if err := d.get(ctx, "GetMatchDetails", params, &mdetailwrp); err != nil {
+		return mdetailwrp.Result, err
 	}
-	mdetail = mdetailwrp.Result
-	return mdetail, nil
+	return mdetailwrp.Result, nil
 }
 
-//GetLeagueListing will get a list of leagues which can be viewed within DotaTV.
-func (d *Dota2api) GetLeagueListing() (LeagueList, error) {
-	var leagues LeagueList
+//GetMatchHistoryBySeqNum will get full match details of matches in the order they were recorded,
+//starting at match_seq_num startSeq. count is the number of matches to return(API caps it at 100).
+//example:
+//	GetMatchHistoryBySeqNum(3445000000, 100)
+//return:
+//	the detailed information of matches ordered by MatchSeqNum.
+func (d *Dota2api) GetMatchHistoryBySeqNum(startSeq int64, count int) (MatchHistoryBySeqNum, error) {
+	return d.GetMatchHistoryBySeqNumContext(context.Background(), startSeq, count)
+}
 
-	url, found := URLMap["GetLeagueListing"]
-	if !found {
-		return leagues, URLMapError
+//GetMatchHistoryBySeqNumContext is like GetMatchHistoryBySeqNum but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetMatchHistoryBySeqNumContext(ctx context.Context, startSeq int64, count int) (MatchHistoryBySeqNum, error) {
+	var mhseqwrap MatchHistoryBySeqNumWrapper
+	if startSeq < 0 {
+		return mhseqwrap.Result, fmt.Errorf("%w: start_at_match_seq_num must not be negative, got %d", ErrInvalidParameter, startSeq)
+	}
+	if err := validateCount("matches_requested", count); err != nil {
+		return mhseqwrap.Result, err
 	}
 
-	formurl := url + "?key=" + d.apikey
-	log.Printf("formurl=%s\n", formurl)
-	bleagues, err := d.RequestForURL(formurl)
-	if err != nil {
-		return leagues, err
+	params := url.Values{
+		"start_at_match_seq_num": {strconv.FormatInt(startSeq, 10)},
+		"matches_requested":      {strconv.Itoa(count)},
 	}
-	//log.Printf("bytes->\n%s\n", bleagues)
+	if err := d.get(ctx, "GetMatchHistoryBySeqNum", params, &mhseqwrap); err != nil {
+		return mhseqwrap.Result, err
+	}
+
+	mhseq := mhseqwrap.Result
+	sort.SliceStable(mhseq.Matches, func(i, j int) bool {
+		return mhseq.Matches[i].MatchSeqNum < mhseq.Matches[j].MatchSeqNum
+	})
+	return mhseq, nil
+}
+
+//GetLeagueListing will get a list of leagues which can be viewed within DotaTV.
+func (d *Dota2api) GetLeagueListing() (LeagueList, error) {
+	return d.GetLeagueListingContext(context.Background())
+}
 
+//GetLeagueListingContext is like GetLeagueListing but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetLeagueListingContext(ctx context.Context) (LeagueList, error) {
 	var leaguelistwrapper LeagueListWrapper
-	err = json.Unmarshal(bleagues, &leaguelistwrapper)
-	if err != nil {
-		return leagues, err
+	if err := d.get(ctx, "GetLeagueListing", nil, &leaguelistwrapper); err != nil {
+		return leaguelistwrapper.League, err
 	}
+	return leaguelistwrapper.League, nil
+}
 
-	leagues = leaguelistwrapper.League
+//GetTeamInfoByTeamID will get a list of teams' information, starting at team id startAtTeamID.
+//teamsRequested is the number of teams to return.
+//example:
+//	GetTeamInfoByTeamID(2586976, 1)
+//return:
+//	list of teaminfo, eg: TeamInfo{TeamID:2586976, Name:"OG", Tag:"OG", ...}
+func (d *Dota2api) GetTeamInfoByTeamID(startAtTeamID int64, teamsRequested int) (TeamInfoList, error) {
+	return d.GetTeamInfoByTeamIDContext(context.Background(), startAtTeamID, teamsRequested)
+}
 
-	return leagues, nil
+//GetTeamInfoByTeamIDContext is like GetTeamInfoByTeamID but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetTeamInfoByTeamIDContext(ctx context.Context, startAtTeamID int64, teamsRequested int) (TeamInfoList, error) {
+	var teaminfowrap TeamInfoWrapper
+	if startAtTeamID < 0 {
+		return teaminfowrap.Result, fmt.Errorf("%w: start_at_team_id must not be negative, got %d", ErrInvalidParameter, startAtTeamID)
+	}
+	if err := validateCount("teams_requested", teamsRequested); err != nil {
+		return teaminfowrap.Result, err
+	}
 
+	params := url.Values{
+		"start_at_team_id": {strconv.FormatInt(startAtTeamID, 10)},
+		"teams_requested":  {strconv.Itoa(teamsRequested)},
+	}
+	if err := d.get(ctx, "GetTeamInfoByTeamId", params, &teaminfowrap); err != nil {
+		return teaminfowrap.Result, err
+	}
+	return teaminfowrap.Result, nil
 }
 
-//GetPlayerSummaries will get basic profile information for 64-bit Steam IDs.
-//API itself supports list of commma-delimated steam ids. eg: "71210000,7456222"
+//GetPlayerSummaries will get basic profile information for Steam IDs, they're sent in the 64-bit form.
 //example:
-//	GetPlayerSummaries("71210000,7456222")
+//	GetPlayerSummaries(76561198092165728, 76561197960435530)
 //return:
 //	list of playersummary
-func (d *Dota2api) GetPlayerSummaries(steamids string) (PlayerSummaryList, error) {
-	var plsummarylist PlayerSummaryList
-	url, found := URLMap["GetPlayerSummaries"]
-	if !found {
-		return plsummarylist, URLMapError
-	}
+func (d *Dota2api) GetPlayerSummaries(steamids ...SteamID) (PlayerSummaryList, error) {
+	return d.GetPlayerSummariesContext(context.Background(), steamids...)
+}
 
-	formurl := url + "?key=" + d.apikey + "&steamids=" + steamids
-	bplayersummary, err := d.RequestForURL(formurl)
-	if err != nil {
-		return plsummarylist, err
+//GetPlayerSummariesContext is like GetPlayerSummaries but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetPlayerSummariesContext(ctx context.Context, steamids ...SteamID) (PlayerSummaryList, error) {
+	var playersmrwrp PlayerSummaryWrapper
+	if len(steamids) == 0 {
+		return playersmrwrp.Response, fmt.Errorf("%w: no steam id", ErrInvalidSteamID)
+	}
+	ids := make([]string, len(steamids))
+	for i, steamid := range steamids {
+		steamid = steamid.canonical()
+		if !steamid.IsValid() {
+			return playersmrwrp.Response, fmt.Errorf("%w: %d", ErrInvalidSteamID, uint64(steamid))
+		}
+		ids[i] = steamid.String()
 	}
 
-	var playersmrwrp PlayerSummaryWrapper
-	err = json.Unmarshal(bplayersummary, &playersmrwrp)
-	if err != nil {
-		return plsummarylist, err
+	params := url.Values{"steamids": {strings.Join(ids, ",")}}
+	if err := d.get(ctx, "GetPlayerSummaries", params, &playersmrwrp); err != nil {
+		return playersmrwrp.Response, err
 	}
-	plsummarylist = playersmrwrp.Response
-	return plsummarylist, err
+	return playersmrwrp.Response, nil
 }
 
 //GetFriendList returns the friend list of any Steam user, only if Steam Community profile visibility is set to "Public".
 //Nothing will be returned if the profile is private.
 //example:
-//	GetFriendList("76561198092165728", "friend")
+//	GetFriendList(76561198092165728, "friend")
 //return:
 //	slice of struct FriendInfo
-func (d *Dota2api) GetFriendList(steamid string, relationship string) ([]FriendInfo, error) {
-	var friendlist []FriendInfo
-	url, found := URLMap["GetFriendList"]
-	if !found {
-		return friendlist, URLMapError
-	}
-
-	formurl := url + "?key=" + d.apikey + "&steamid=" + steamid + "&relationship=" + relationship
-	bfriendlist, err := d.RequestForURL(formurl)
-	if err != nil {
-		return friendlist, err
-	}
+func (d *Dota2api) GetFriendList(steamid SteamID, relationship string) ([]FriendInfo, error) {
+	return d.GetFriendListContext(context.Background(), steamid, relationship)
+}
 
+//GetFriendListContext is like GetFriendList but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetFriendListContext(ctx context.Context, steamid SteamID, relationship string) ([]FriendInfo, error) {
 	var frdlistwrap FriendListWrapper
-	err = json.Unmarshal(bfriendlist, &frdlistwrap)
-	if err != nil {
-		return friendlist, err
-
+	steamid = steamid.canonical()
+	if !steamid.IsValid() {
+		return frdlistwrap.FriendList.Friends, fmt.Errorf("%w: %d", ErrInvalidSteamID, uint64(steamid))
 	}
-	friendlist = frdlistwrap.FriendList.Friends
 
-	return friendlist, err
+	params := url.Values{
+		"steamid":      {steamid.String()},
+		"relationship": {relationship},
+	}
+	if err := d.get(ctx, "GetFriendList", params, &frdlistwrap); err != nil {
+		return frdlistwrap.FriendList.Friends, err
+	}
+	return frdlistwrap.FriendList.Friends, nil
 }
 
 //GetServerInfo will return WebAPI Server's time info.
 func (d *Dota2api) GetServerInfo() (ServerInfo, error) {
+	return d.GetServerInfoContext(context.Background())
+}
+
+//GetServerInfoContext is like GetServerInfo but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetServerInfoContext(ctx context.Context) (ServerInfo, error) {
 	var srvinfo ServerInfo
-	url, found := URLMap["GetServerInfo"]
-	if !found {
-		return srvinfo, URLMapError
+	if err := d.get(ctx, "GetServerInfo", nil, &srvinfo); err != nil {
+		return srvinfo, err
 	}
+	return srvinfo, nil
+}
 
-	bsrvinfo, err := d.RequestForURL(url)
-	if err != nil {
-		return srvinfo, err
+//GetHeroes will get the list of all heroes, language is an ISO639-1 code like "en_us" or "zh_cn",
+//"" means the language set by WithLanguage, LocalizedName will be empty when neither is set.
+//example:
+//	GetHeroes("en_us")
+//return:
+//	list of hero, eg: Hero{Name:"npc_dota_hero_antimage", ID:1, LocalizedName:"Anti-Mage"}
+func (d *Dota2api) GetHeroes(language string) (HeroList, error) {
+	return d.GetHeroesContext(context.Background(), language)
+}
+
+//GetHeroesContext is like GetHeroes but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetHeroesContext(ctx context.Context, language string) (HeroList, error) {
+	var herolistwrap HeroListWrapper
+	if language == "" {
+		language = d.language
 	}
 
-	err = json.Unmarshal(bsrvinfo, &srvinfo)
-	if err != nil {
-		return srvinfo, err
+	params := url.Values{}
+	if language != "" {
+		params.Set("language", language)
+	}
+	if err := d.get(ctx, "GetHeroes", params, &herolistwrap); err != nil {
+		return herolistwrap.Result, err
 	}
+	return herolistwrap.Result, nil
+}
 
-	return srvinfo, nil
+//GetGameItems will get the list of all items, language is an ISO639-1 code like "en_us" or "zh_cn",
+//"" means the language set by WithLanguage, LocalizedName will be empty when neither is set.
+//example:
+//	GetGameItems("en_us")
+//return:
+//	list of item, eg: Item{ID:1, Name:"item_blink", Cost:2250, SideShop:1, LocalizedName:"Blink Dagger"}
+func (d *Dota2api) GetGameItems(language string) (ItemList, error) {
+	return d.GetGameItemsContext(context.Background(), language)
+}
+
+//GetGameItemsContext is like GetGameItems but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetGameItemsContext(ctx context.Context, language string) (ItemList, error) {
+	var itemlistwrap ItemListWrapper
+	if language == "" {
+		language = d.language
+	}
+
+	params := url.Values{}
+	if language != "" {
+		params.Set("language", language)
+	}
+	if err := d.get(ctx, "GetGameItems", params, &itemlistwrap); err != nil {
+		return itemlistwrap.Result, err
+	}
+	return itemlistwrap.Result, nil
+}
+
+//GetTournamentPrizePool will get the current prize pool of a league, including the crowdfunded part.
+//example:
+//	GetTournamentPrizePool(9870)
+//return:
+//	PrizePool{PrizePool:25532177, LeagueID:9870, Status:200}
+func (d *Dota2api) GetTournamentPrizePool(leagueID int) (PrizePool, error) {
+	return d.GetTournamentPrizePoolContext(context.Background(), leagueID)
+}
+
+//GetTournamentPrizePoolContext is like GetTournamentPrizePool but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetTournamentPrizePoolContext(ctx context.Context, leagueID int) (PrizePool, error) {
+	var prizepoolwrap PrizePoolWrapper
+	if leagueID < 0 {
+		return prizepoolwrap.Result, fmt.Errorf("%w: leagueid must not be negative, got %d", ErrInvalidParameter, leagueID)
+	}
+
+	params := url.Values{"leagueid": {strconv.Itoa(leagueID)}}
+	if err := d.get(ctx, "GetTournamentPrizePool", params, &prizepoolwrap); err != nil {
+		return prizepoolwrap.Result, err
+	}
+	return prizepoolwrap.Result, nil
 }
 
 //GetLiveLeagueGames will return list of the detailed and real-time statistics of the games which are being played.
 func (d *Dota2api) GetLiveLeagueGames() (LeagueGames, error) {
-	var (
-		leaguegameswarp LeagueGamesWrapper
-		leaguegames     LeagueGames
-	)
-	url, found := URLMap["GetLiveLeagueGames"]
-	if !found {
-		return leaguegames, URLMapError
-	}
-
-	formurl := url + "?key=" + d.apikey
-	log.Println("formurl->", formurl)
-	bleagues, err := d.RequestForURL(formurl)
-	if err != nil {
-		return leaguegames, err
-	}
-	//log.Printf("bleages->\n%s", bleagues)
+	return d.GetLiveLeagueGamesContext(context.Background())
+}
 
-	err = json.Unmarshal(bleagues, &leaguegameswarp)
-	if err != nil {
-		return leaguegames, err
+//GetLiveLeagueGamesContext is like GetLiveLeagueGames but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetLiveLeagueGamesContext(ctx context.Context) (LeagueGames, error) {
+	var leaguegameswarp LeagueGamesWrapper
+	if err := d.get(ctx, "GetLiveLeagueGames", nil, &leaguegameswarp); err != nil {
+		return leaguegameswarp.LgGames, err
 	}
+	return leaguegameswarp.LgGames, nil
+}
 
-	leaguegames = leaguegameswarp.LgGames
-	return leaguegames, nil
+//GetTopLiveGame will get the top public and league games which are being played, as listed in the client's Watch tab.
+//partner selects the list of games, 0 is the default one.
+//example:
+//	GetTopLiveGame(0)
+//return:
+//	list of TopLiveGame ordered by SortScore
+func (d *Dota2api) GetTopLiveGame(partner int) (TopLiveGames, error) {
+	return d.GetTopLiveGameContext(context.Background(), partner)
+}
 
+//GetTopLiveGameContext is like GetTopLiveGame but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetTopLiveGameContext(ctx context.Context, partner int) (TopLiveGames, error) {
+	var toplivegames TopLiveGames
+	params := url.Values{"partner": {strconv.Itoa(partner)}}
+	if err := d.get(ctx, "GetTopLiveGame", params, &toplivegames); err != nil {
+		return toplivegames, err
+	}
+	return toplivegames, nil
 }
 
-//RequestForURL will send http request to url and return the result with []byte
+//RequestForURL will send http request to url and return the result with []byte,
+//a non-2xx response is returned as *APIError. The request waits for the rate limit set by SetRateLimit first,
+//and transient failures are retried according to SetRetryPolicy.
 func (d *Dota2api) RequestForURL(url string) ([]byte, error) {
+	return d.RequestForURLContext(context.Background(), url)
+}
+
+//RequestForURLContext is like RequestForURL but the request is bound to ctx,
+//it's cancelled when ctx is done and follows ctx's deadline, including the waiting time between retries.
+func (d *Dota2api) RequestForURLContext(ctx context.Context, url string) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		bresp, err := d.requestOnce(ctx, url)
+		if err == nil || attempt >= d.retry.MaxAttempts || ctx.Err() != nil {
+			return bresp, err
+		}
+
+		delay, retryable := d.retry.backoff(attempt, err)
+		if !retryable {
+			return bresp, err
+		}
+		d.logger.Warn("steam web api request will be retried", "url", redactURL(url),
+			"attempt", attempt, "delay", delay, "error", err)
+		if err := sleepContext(ctx, delay); err != nil {
+			return bresp, err
+		}
+	}
+}
+
+//requestOnce sends a single request to url.
+func (d *Dota2api) requestOnce(ctx context.Context, url string) ([]byte, error) {
 	var bresp []byte
-	resp, err := d.client.Get(url)
+	endpoint := d.endpointName(url)
+	if err := d.limits.wait(ctx, endpoint); err != nil {
+		return bresp, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return bresp, err
+	}
+	if d.userAgent != "" {
+		req.Header.Set("User-Agent", d.userAgent)
+	}
+
+	d.logger.Debug("steam web api request", "endpoint", endpoint, "url", redactURL(url))
+	start := time.Now()
+	resp, err := d.client.Do(req)
 	if err != nil {
+		err = redactError(err)
+		d.logger.Debug("steam web api request failed", "endpoint", endpoint, "error", err)
 		return bresp, err
 	}
 	defer resp.Body.Close()
@@ -305,6 +485,16 @@ func (d *Dota2api) RequestForURL(url string) ([]byte, error) {
 	if err != nil {
 		return bresp, err
 	}
+	d.logger.Debug("steam web api response", "endpoint", endpoint, "status", resp.StatusCode,
+		"bytes", len(bresp), "elapsed", time.Since(start))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return bresp, newAPIError(endpoint, resp, url, bresp)
+	}
+
+	if len(bytes.TrimSpace(bresp)) == 0 {
+		return bresp, ErrEmptyResponse
+	}
 
 	return bresp, nil
 }
@@ -1,28 +1,53 @@
 package dota2
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
 	"testing"
 	"time"
+
+	"github.com/Katsusan/go-dota2/dota2test"
 )
 
-func TestGetMatchHistory(t *testing.T) {
-	dapi := NewApi(nil)
-	dapi.SetApiKey("E09635A9F555CE8F0B0CCEECE8E40434")
-	mh, err := dapi.GetMatchHistory("131900000d")
-	if err != nil {
-		t.Errorf("GetMatchHistory failed,%v\n", err)
+const (
+	LIVE_TEST_ENV = "DOTA2_LIVE_TEST" //set to run the tests against the real Steam Web API
+	TEST_APIKEY   = "E09635A9F555CE8F0B0CCEECE8E40434"
+)
+
+//newLiveApi returns a Dota2api for the real Steam Web API, the test is skipped unless DOTA2_LIVE_TEST is set.
+func newLiveApi(t *testing.T) *Dota2api {
+	if os.Getenv(LIVE_TEST_ENV) == "" {
+		t.Skip("set " + LIVE_TEST_ENV + "=1 to run against the real Steam Web API")
 	}
-	log.Println(mh.Status)
-	if mh.Status != 1 {
-		t.Errorf("When invalid characters are in dota2 id , MatchHistory.Status should be 1, Got:%d\n", mh.Status)
+	dapi := NewApi(nil)
+	dapi.SetApiKey(TEST_APIKEY)
+	return dapi
+}
+
+//newOfflineApi returns a Dota2api talking to a dota2test.Server serving the built-in fixtures.
+func newOfflineApi(t *testing.T) (*Dota2api, *dota2test.Server) {
+	srv := dota2test.NewServer()
+	t.Cleanup(srv.Close)
+	return New(WithBaseURL(srv.URL), WithAPIKey(TEST_APIKEY)), srv
+}
+
+func TestGetMatchHistory(t *testing.T) {
+	dapi := newLiveApi(t)
+	_, err := dapi.GetMatchHistory(0)
+	if !errors.Is(err, ErrInvalidAccountID) {
+		t.Errorf("When dota2 id is 0, GetMatchHistory should fail with ErrInvalidAccountID, Got:%v\n", err)
 	}
 
-	mhabnor, err := dapi.GetMatchHistory("1531490000111111110")
-	if err != nil {
-		t.Errorf("GetMatchHistory failed,%v\n", err)
+	mhabnor, err := dapi.GetMatchHistory(NewSteamID(2041547718))
+	if !errors.Is(err, ErrPrivateProfile) {
+		t.Errorf("GetMatchHistory should fail with ErrPrivateProfile, Got:%v\n", err)
 	}
 	if mhabnor.Status != 15 {
 		log.Printf("%+v\n", mhabnor)
@@ -30,7 +55,7 @@ func TestGetMatchHistory(t *testing.T) {
 
 	}
 
-	mhnor, err := dapi.GetMatchHistory("131900000")
+	mhnor, err := dapi.GetMatchHistory(NewSteamID(131900000))
 	if err != nil {
 		t.Errorf("GetMatchHistory failed,%v\n", err)
 	}
@@ -40,8 +65,7 @@ func TestGetMatchHistory(t *testing.T) {
 }
 
 func TestGetMatchDetails(t *testing.T) {
-	dapi := NewApi(nil)
-	dapi.SetApiKey("E09635A9F555CE8F0B0CCEECE8E40434")
+	dapi := newLiveApi(t)
 	mtd, err := dapi.GetMatchDetails("4080856812") // Match:4080856812 -> the 5th match of TI8 Grand Final(BO5)
 	if err != nil {
 		t.Errorf("GetMatchDetails request failed.")
@@ -67,10 +91,59 @@ func TestGetMatchDetails(t *testing.T) {
 	}
 }
 
+func TestGetMatchHistoryBySeqNum(t *testing.T) {
+	dapi, srv := newOfflineApi(t)
+	//Steam doesn't guarantee the order of matches, the result must be sorted anyway
+	srv.SetFixture("GetMatchHistoryBySequenceNum", []byte(`{"result":{"status":1,"matches":[
+		{"match_id":4080856812,"match_seq_num":3445000002},
+		{"match_id":4080856810,"match_seq_num":3445000000},
+		{"match_id":4080856811,"match_seq_num":3445000001}]}}`))
+
+	var startseq int64 = 3445000000
+	mhseq, err := dapi.GetMatchHistoryBySeqNum(startseq, 3)
+	if err != nil {
+		t.Errorf("GetMatchHistoryBySeqNum failed,%v\n", err)
+	}
+
+	if mhseq.Status != 1 {
+		t.Errorf("MatchHistoryBySeqNum.Status should be 1, Got:%d\n", mhseq.Status)
+	}
+
+	if len(mhseq.Matches) != 3 {
+		t.Fatalf("Expected 3 matches, Got:%d\n", len(mhseq.Matches))
+	}
+
+	for i, match := range mhseq.Matches {
+		if match.MatchSeqNum != startseq+int64(i) {
+			t.Errorf("Matches are not in sequence order, Got %d at %d.\n", match.MatchSeqNum, i)
+		}
+	}
+
+	query := srv.Requests()[0].Query
+	if query.Get("start_at_match_seq_num") != "3445000000" || query.Get("matches_requested") != "3" {
+		t.Errorf("Unexpected parameters:%v\n", query)
+	}
+}
+
+func TestGetTeamInfoByTeamID(t *testing.T) {
+	dapi, _ := newOfflineApi(t)
+	teaminfolist, err := dapi.GetTeamInfoByTeamID(2586976, 1) // Team:2586976 -> OG, TI8 champion
+	if err != nil {
+		t.Errorf("GetTeamInfoByTeamID request failed.%s\n", err)
+	}
+
+	if len(teaminfolist.Teams) != 1 {
+		t.Fatalf("Expected 1 team, Got:%d\n", len(teaminfolist.Teams))
+	}
+
+	if teaminfolist.Teams[0].TeamID != 2586976 || teaminfolist.Teams[0].Tag != "OG" || len(teaminfolist.Teams[0].PlayerAccountIDs) != 5 {
+		t.Errorf("Got unexpected team:%+v, Expected OG(2586976)\n", teaminfolist.Teams[0])
+	}
+}
+
 func TestGetPlayerSummaries(t *testing.T) {
-	dapi := NewApi(nil)
-	dapi.SetApiKey("E09635A9F555CE8F0B0CCEECE8E40434")
-	psummarylist, err := dapi.GetPlayerSummaries("76561198092165728,76561197960435530")
+	dapi := newLiveApi(t)
+	psummarylist, err := dapi.GetPlayerSummaries(76561198092165728, 76561197960435530)
 
 	if err != nil {
 		fmt.Println(err)
@@ -85,9 +158,8 @@ func TestGetPlayerSummaries(t *testing.T) {
 }
 
 func TestGetFriendList(t *testing.T) {
-	dapi := NewApi(nil)
-	dapi.SetApiKey("E09635A9F555CE8F0B0CCEECE8E40434")
-	frdlist, err := dapi.GetFriendList("76561198092165728", "friend")
+	dapi := newLiveApi(t)
+	frdlist, err := dapi.GetFriendList(76561198092165728, "friend")
 	if err != nil {
 		t.Errorf("GetFriendList request failed.%s\n", err)
 	}
@@ -111,8 +183,7 @@ func TestGetFriendList(t *testing.T) {
 }
 
 func TestGetLeagueListing(t *testing.T) {
-	dapi := NewApi(nil)
-	dapi.SetApiKey("E09635A9F555CE8F0B0CCEECE8E40434")
+	dapi := newLiveApi(t)
 	leagues, err := dapi.GetLeagueListing()
 	if err != nil {
 		t.Errorf("GetLeagueListing failed.%s\n", err)
@@ -142,8 +213,7 @@ func TestGetLeagueListing(t *testing.T) {
 }
 
 func TestGetLiveLeagueGames(t *testing.T) {
-	dapi := NewApi(nil)
-	dapi.SetApiKey("E09635A9F555CE8F0B0CCEECE8E40434")
+	dapi := newLiveApi(t)
 
 	leagues, err := dapi.GetLiveLeagueGames()
 	if err != nil {
@@ -156,8 +226,72 @@ func TestGetLiveLeagueGames(t *testing.T) {
 
 }
 
+func TestGetHeroes(t *testing.T) {
+	dapi, _ := newOfflineApi(t)
+	herolist, err := dapi.GetHeroes("en_us")
+	if err != nil {
+		t.Errorf("GetHeroes failed.%s\n", err)
+	}
+
+	reg := NewHeroRegistry(herolist.Heroes)
+	if reg.Len() != herolist.Count {
+		t.Errorf("Registry has %d heroes, Expected:%d\n", reg.Len(), herolist.Count)
+	}
+
+	if reg.Name(1) != "npc_dota_hero_antimage" || reg.LocalizedName(1) != "Anti-Mage" {
+		t.Errorf("Hero 1 should be Anti-Mage, Got:%s(%s)\n", reg.LocalizedName(1), reg.Name(1))
+	}
+}
+
+func TestGetGameItems(t *testing.T) {
+	dapi, _ := newOfflineApi(t)
+	itemlist, err := dapi.GetGameItems("en_us")
+	if err != nil {
+		t.Errorf("GetGameItems failed.%s\n", err)
+	}
+
+	reg := NewItemRegistry(itemlist.Items)
+	blink, found := reg.Item(1)
+	if !found || blink.Name != "item_blink" || blink.LocalizedName != "Blink Dagger" {
+		t.Errorf("Item 1 should be Blink Dagger, Got:%+v\n", blink)
+	}
+}
+
+func TestGetTournamentPrizePool(t *testing.T) {
+	dapi, _ := newOfflineApi(t)
+	prizepool, err := dapi.GetTournamentPrizePool(9870) // League:9870 -> The International 2018
+	if err != nil {
+		t.Errorf("GetTournamentPrizePool failed.%s\n", err)
+	}
+
+	if prizepool.LeagueID != 9870 || prizepool.PrizePool != 25532177 {
+		t.Errorf("Got unexpected prize pool of TI8:%+v\n", prizepool)
+	}
+}
+
+func TestGetTopLiveGame(t *testing.T) {
+	dapi, _ := newOfflineApi(t)
+	toplivegames, err := dapi.GetTopLiveGame(0)
+	if err != nil {
+		t.Errorf("GetTopLiveGame failed.%s\n", err)
+	}
+
+	if len(toplivegames.Games) != 1 {
+		t.Fatalf("Expected 1 live game, Got:%d\n", len(toplivegames.Games))
+	}
+	game := toplivegames.Games[0]
+	if game.MatchID != 4216074906 || game.LobbyID != 25867287180814999 || game.AverageMMR != 7800 ||
+		game.GameTime != 1260 || game.RadiantLead != 3200 || game.RadiantScore != 21 || game.DireScore != 14 ||
+		game.BuildingState != 4784201 {
+		t.Errorf("Got unexpected live game:%+v\n", game)
+	}
+	if len(game.Players) != 10 || game.Players[0].AccountID != 100000000 || game.Players[0].HeroID != 8 {
+		t.Errorf("Got unexpected players:%+v\n", game.Players)
+	}
+}
+
 func TestGetServerInfo(t *testing.T) {
-	dapi := NewApi(nil)
+	dapi := newLiveApi(t)
 
 	srverinfo, err := dapi.GetServerInfo()
 	if err != nil {
@@ -168,3 +302,111 @@ func TestGetServerInfo(t *testing.T) {
 		t.Errorf("Server time(%d) isn't consistent with localtime(%d).\n", srverinfo.ServerTime, tm)
 	}
 }
+
+func TestRequestForURLContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	dapi := NewApi(nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := dapi.RequestForURLContext(ctx, srv.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Request should fail with context.DeadlineExceeded, Got:%v\n", err)
+	}
+}
+
+func TestNewWithOptions(t *testing.T) {
+	var gotpath, gotagent, gotlanguage string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotpath, gotagent, gotlanguage = r.URL.Path, r.UserAgent(), r.URL.Query().Get("language")
+		w.Write([]byte(`{"result":{"heroes":[],"status":200,"count":0}}`))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{}
+	dapi := New(
+		WithAPIKey("E09635A9F555CE8F0B0CCEECE8E40434"),
+		WithTimeout(5*time.Second),
+		WithHTTPClient(client),
+		WithBaseURL(srv.URL),
+		WithUserAgent("go-dota2-test"),
+		WithLanguage("zh_cn"),
+	)
+
+	if _, err := dapi.GetHeroes(""); err != nil {
+		t.Fatalf("GetHeroes failed, %v\n", err)
+	}
+	if gotpath != "/IEconDOTA2_570/GetHeroes/v0001/" || gotagent != "go-dota2-test" || gotlanguage != "zh_cn" {
+		t.Errorf("Got path:%s, User-Agent:%s, language:%s\n", gotpath, gotagent, gotlanguage)
+	}
+	if dapi.client.Timeout != 5*time.Second || client.Timeout != 0 {
+		t.Errorf("WithTimeout should apply to a copy of the client, Got:%v, original:%v\n", dapi.client.Timeout, client.Timeout)
+	}
+}
+
+func TestEndpointVersion(t *testing.T) {
+	var gotpath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotpath = r.URL.Path
+		w.Write([]byte(`{"result":{"status":1}}`))
+	}))
+	defer srv.Close()
+
+	bumped := New(WithBaseURL(srv.URL), WithEndpointVersion("GetMatchDetails", "v002"))
+	original := New(WithBaseURL(srv.URL))
+
+	bumped.GetMatchDetails("4080856812")
+	if gotpath != "/IDOTA2Match_570/GetMatchDetails/v002/" {
+		t.Errorf("Got path:%s, Expected version v002\n", gotpath)
+	}
+	original.GetMatchDetails("4080856812")
+	if gotpath != "/IDOTA2Match_570/GetMatchDetails/v001/" {
+		t.Errorf("Other instances should keep v001, Got path:%s\n", gotpath)
+	}
+
+	if name := bumped.endpointName(srv.URL + "/IDOTA2Match_570/GetMatchDetails/v002/?key=x"); name != "GetMatchDetails" {
+		t.Errorf("endpointName should find GetMatchDetails, Got:%s\n", name)
+	}
+	if err := bumped.SetEndpointVersion("GetUnknown", "v1"); err != URLMapError {
+		t.Errorf("Unknown endpoint should return URLMapError, Got:%v\n", err)
+	}
+}
+
+func TestRequestParameters(t *testing.T) {
+	var gotquery url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotquery = r.URL.Query()
+		w.Write([]byte(`{"friendslist":{"friends":[]}}`))
+	}))
+	defer srv.Close()
+
+	dapi := New(WithBaseURL(srv.URL), WithAPIKey("E09635A9F555CE8F0B0CCEECE8E40434"))
+	if _, err := dapi.GetFriendList(76561198092165728, "friend&all"); err != nil {
+		t.Fatalf("GetFriendList failed, %v\n", err)
+	}
+	if gotquery.Get("relationship") != "friend&all" || gotquery.Get("key") != "E09635A9F555CE8F0B0CCEECE8E40434" {
+		t.Errorf("Parameters should be escaped, Got:%v\n", gotquery)
+	}
+
+	gotquery = nil
+	for _, err := range []error{
+		func() error { _, err := dapi.GetMatchDetails("4080856812&key=x"); return err }(),
+		func() error { _, err := dapi.GetMatchHistory(0); return err }(),
+		func() error { _, err := dapi.GetPlayerSummaries(76561198092165728, 1531490000111111110); return err }(),
+		func() error { _, err := dapi.GetPlayerSummaries(); return err }(),
+		func() error { _, err := dapi.GetMatchHistoryBySeqNum(1, 0); return err }(),
+	} {
+		if err == nil {
+			t.Errorf("Malformed parameters should be rejected\n")
+		}
+	}
+	if gotquery != nil {
+		t.Errorf("Malformed requests should not be sent, Got:%v\n", gotquery)
+	}
+}
@@ -0,0 +1,169 @@
+package dota2
+
+//Building is a tower or barracks of one team, the value is its position in BuildingState.
+type Building int
+
+const (
+	BUILDING_TOWER_TOP_1 Building = iota
+	BUILDING_TOWER_TOP_2
+	BUILDING_TOWER_TOP_3
+	BUILDING_TOWER_MID_1
+	BUILDING_TOWER_MID_2
+	BUILDING_TOWER_MID_3
+	BUILDING_TOWER_BOT_1
+	BUILDING_TOWER_BOT_2
+	BUILDING_TOWER_BOT_3
+	BUILDING_TOWER_ANCIENT_TOP //tier 4 towers guarding the ancient
+	BUILDING_TOWER_ANCIENT_BOT
+	BUILDING_BARRACKS_TOP_MELEE
+	BUILDING_BARRACKS_TOP_RANGED
+	BUILDING_BARRACKS_MID_MELEE
+	BUILDING_BARRACKS_MID_RANGED
+	BUILDING_BARRACKS_BOT_MELEE
+	BUILDING_BARRACKS_BOT_RANGED
+
+	TOWERCOUNT    = 11
+	BARRACKSCOUNT = 6
+)
+
+//Lane is one of the three lanes of the map.
+type Lane int
+
+const (
+	LANE_TOP Lane = iota
+	LANE_MID
+	LANE_BOT
+)
+
+//bit positions of the live format of GetLiveLeagueGames, observed from games with every building standing:
+//tower_state 0xffc00001 -> towers in post-game order at bits 22-31 except the bottom tier 4 at bit 0,
+//barracks_state 0x3f000 -> barracks in post-game order at bits 12-17.
+const (
+	liveTowerShift        = 22
+	liveAncientBotTower   = 0
+	liveBarracksShift     = 12
+	postgameTowerMask     = 1<<TOWERCOUNT - 1
+	postgameBarracksMask  = 1<<BARRACKSCOUNT - 1
+	postgameAncientBotBit = 1 << (TOWERCOUNT - 1)
+)
+
+var buildingNames = [...]string{
+	"top tier 1 tower", "top tier 2 tower", "top tier 3 tower",
+	"mid tier 1 tower", "mid tier 2 tower", "mid tier 3 tower",
+	"bot tier 1 tower", "bot tier 2 tower", "bot tier 3 tower",
+	"top tier 4 tower", "bot tier 4 tower",
+	"top melee barracks", "top ranged barracks",
+	"mid melee barracks", "mid ranged barracks",
+	"bot melee barracks", "bot ranged barracks",
+}
+
+func (b Building) String() string {
+	if b < 0 || int(b) >= len(buildingNames) {
+		return "unknown building"
+	}
+	return buildingNames[b]
+}
+
+//IsTower reports whether b is a tower, otherwise it's a barracks.
+func (b Building) IsTower() bool {
+	return b < BUILDING_BARRACKS_TOP_MELEE
+}
+
+func (l Lane) String() string {
+	switch l {
+	case LANE_TOP:
+		return "top"
+	case LANE_MID:
+		return "mid"
+	case LANE_BOT:
+		return "bot"
+	}
+	return "unknown lane"
+}
+
+//BuildingState is the towers and barracks of one team, decoded from the bitmasks of Steam where a set bit means standing.
+//Towers use the bits 0-10 in the order of BUILDING_TOWER_xx, barracks the bits 0-5 in the order of BUILDING_BARRACKS_xx.
+type BuildingState struct {
+	Towers   uint16
+	Barracks uint8
+}
+
+//NewBuildingState decodes tower_status_xx and barracks_status_xx of GetMatchDetails.
+func NewBuildingState(towerStatus, barracksStatus int) BuildingState {
+	return BuildingState{
+		Towers:   uint16(towerStatus & postgameTowerMask),
+		Barracks: uint8(barracksStatus & postgameBarracksMask),
+	}
+}
+
+//NewLiveBuildingState decodes tower_state and barracks_state of a team in the scoreboard of GetLiveLeagueGames.
+func NewLiveBuildingState(towerState int64, barracksState int32) BuildingState {
+	towers := uint16(towerState>>liveTowerShift) & (postgameTowerMask >> 1)
+	if towerState&(1<<liveAncientBotTower) != 0 {
+		towers |= postgameAncientBotBit
+	}
+	return BuildingState{
+		Towers:   towers,
+		Barracks: uint8(barracksState>>liveBarracksShift) & postgameBarracksMask,
+	}
+}
+
+//IsStanding reports whether building b hasn't been destroyed.
+func (bs BuildingState) IsStanding(b Building) bool {
+	switch {
+	case b < 0 || b > BUILDING_BARRACKS_BOT_RANGED:
+		return false
+	case b.IsTower():
+		return bs.Towers&(1<<uint(b)) != 0
+	default:
+		return bs.Barracks&(1<<uint(b-BUILDING_BARRACKS_TOP_MELEE)) != 0
+	}
+}
+
+//Standing returns the buildings still standing, towers first.
+func (bs BuildingState) Standing() []Building {
+	return bs.filter(true)
+}
+
+//Destroyed returns the buildings which have been destroyed, towers first.
+func (bs BuildingState) Destroyed() []Building {
+	return bs.filter(false)
+}
+
+//LanesOpened returns the lanes whose melee and ranged barracks are both destroyed,
+//the enemy spawns super creeps there, and mega creeps once all three lanes are opened.
+func (bs BuildingState) LanesOpened() []Lane {
+	var lanes []Lane
+	for lane := LANE_TOP; lane <= LANE_BOT; lane++ {
+		melee := BUILDING_BARRACKS_TOP_MELEE + Building(2*lane)
+		if !bs.IsStanding(melee) && !bs.IsStanding(melee+1) {
+			lanes = append(lanes, lane)
+		}
+	}
+	return lanes
+}
+
+func (bs BuildingState) filter(standing bool) []Building {
+	var buildings []Building
+	for b := BUILDING_TOWER_TOP_1; b <= BUILDING_BARRACKS_BOT_RANGED; b++ {
+		if bs.IsStanding(b) == standing {
+			buildings = append(buildings, b)
+		}
+	}
+	return buildings
+}
+
+//RadiantBuildings decodes TowerStatusRadiant and BarracksStatusRadiant.
+func (md MatchDetail) RadiantBuildings() BuildingState {
+	return NewBuildingState(md.TowerStatusRadiant, md.BarracksStatusRadiant)
+}
+
+//DireBuildings decodes TowerStatusDire and BarracksStatusDire.
+func (md MatchDetail) DireBuildings() BuildingState {
+	return NewBuildingState(md.TowerStatusDire, md.BarracksStatusDire)
+}
+
+//Buildings decodes TowerState and BarracksState of the live scoreboard.
+func (ts TeamStatistic) Buildings() BuildingState {
+	return NewLiveBuildingState(ts.TowerState, ts.BarracksState)
+}
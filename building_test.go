@@ -0,0 +1,43 @@
+package dota2
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBuildingState(t *testing.T) {
+	//dire of the GetMatchDetails fixture: 1974 = 0b11110110110, 63 = every barracks
+	bs := NewBuildingState(1974, 63)
+	if fmt.Sprint(bs.Destroyed()) != "[top tier 1 tower mid tier 1 tower bot tier 1 tower]" {
+		t.Errorf("Only tier 1 towers should be destroyed, Got:%v\n", bs.Destroyed())
+	}
+	if len(bs.Standing()) != TOWERCOUNT+BARRACKSCOUNT-3 || !bs.IsStanding(BUILDING_TOWER_ANCIENT_BOT) {
+		t.Errorf("Expected 14 standing buildings, Got:%v\n", bs.Standing())
+	}
+	if bs.LanesOpened() != nil {
+		t.Errorf("No lane should be opened, Got:%v\n", bs.LanesOpened())
+	}
+
+	//bot melee/ranged and mid melee destroyed
+	bs = NewBuildingState(0, 0b001011)
+	if fmt.Sprint(bs.LanesOpened()) != "[bot]" {
+		t.Errorf("Only bot lane should be opened, Got:%v\n", bs.LanesOpened())
+	}
+}
+
+func TestLiveBuildingState(t *testing.T) {
+	//every building standing at the start of a game
+	bs := NewLiveBuildingState(0xffc00001, 0x3f000)
+	if bs != NewBuildingState(0x7ff, 0x3f) {
+		t.Errorf("All buildings should be standing, Got:%+v\n", bs)
+	}
+
+	//radiant of the first game in livinggames.json, bottom lane has been pushed
+	bs = NewLiveBuildingState(2306867201, 258048)
+	if fmt.Sprint(bs.Standing()[:5]) != "[top tier 2 tower top tier 3 tower mid tier 3 tower top tier 4 tower bot tier 4 tower]" {
+		t.Errorf("Unexpected standing towers, Got:%v\n", bs.Standing())
+	}
+	if bs.IsStanding(BUILDING_TOWER_BOT_3) || !bs.IsStanding(BUILDING_BARRACKS_BOT_RANGED) {
+		t.Errorf("Bot tier 3 tower should be destroyed with barracks standing, Got:%+v\n", bs)
+	}
+}
@@ -0,0 +1,221 @@
+package dota2
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha1"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	NoExpiration time.Duration = -1 //ttl of responses which never change, eg: details of a finished match
+
+	DEFAULT_MEMORYCACHE_CAPACITY = 1024
+)
+
+//Cache stores raw responses of Steam Web API, set it with WithCache.
+//Implementations must be safe for concurrent use.
+type Cache interface {
+	//Get returns the response saved for key, false if it's missing or expired.
+	Get(key string) ([]byte, bool)
+	//Set saves the response for key, it expires after ttl unless ttl is NoExpiration.
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+//DefaultCacheTTLs returns the time to live of cached responses per endpoint, endpoints not listed are never cached.
+//GetMatchHistoryBySeqNum isn't cached because the newest sequence numbers keep filling up,
+//and GetServerInfo because it's a clock. Each call returns a new map, override entries with WithCacheTTL.
+func DefaultCacheTTLs() map[string]time.Duration {
+	return map[string]time.Duration{
+		"GetMatchDetails":        NoExpiration,
+		"GetHeroes":              24 * time.Hour,
+		"GetGameItems":           24 * time.Hour,
+		"GetLeagueListing":       time.Hour,
+		"GetTeamInfoByTeamId":    time.Hour,
+		"GetPlayerSummaries":     5 * time.Minute,
+		"GetFriendList":          5 * time.Minute,
+		"GetMatchHistory":        time.Minute,
+		"GetTournamentPrizePool": time.Minute,
+		"GetLiveLeagueGames":     10 * time.Second,
+		"GetTopLiveGame":         10 * time.Second,
+	}
+}
+
+//WithCache caches responses in cache with the ttls of DefaultCacheTTLs, responses aren't cached by default.
+func WithCache(cache Cache) Option {
+	return func(d *Dota2api) {
+		d.cache = cache
+	}
+}
+
+//WithCacheTTL overrides the ttl of endpoint, eg: "GetLiveLeagueGames". 0 disables caching of the endpoint.
+func WithCacheTTL(endpoint string, ttl time.Duration) Option {
+	return func(d *Dota2api) {
+		if ttl == 0 {
+			delete(d.cacheTTLs, endpoint)
+			return
+		}
+		d.cacheTTLs[endpoint] = ttl
+	}
+}
+
+//cacheKey identifies a request by base url, endpoint path and the sorted parameters without api key.
+func (d *Dota2api) cacheKey(endpoint string, params string) string {
+	ep := d.endpoints[endpoint]
+	return d.baseURL + ep.Path() + "?" + params
+}
+
+type cacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time //zero for NoExpiration
+}
+
+func (e *cacheEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+func expiryOf(ttl time.Duration) time.Time {
+	if ttl == NoExpiration {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+//MemoryCache is an in-memory Cache which evicts the least recently used response when it's full.
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	lru      *list.List //front is the most recently used
+}
+
+//NewMemoryCache creates a MemoryCache holding up to capacity responses.
+func NewMemoryCache(capacity int) *MemoryCache {
+	if capacity <= 0 {
+		capacity = DEFAULT_MEMORYCACHE_CAPACITY
+	}
+	return &MemoryCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if entry.expired(time.Now()) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, value: value, expires: expiryOf(ttl)}
+	if elem, found := c.entries[key]; found {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.capacity {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+//Len returns the number of cached responses, including expired ones not evicted yet.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+//DiskCache is a Cache keeping every response in a file of a directory, so it survives restarts.
+//The first line of a file is the expiry as unix nanoseconds(0 for NoExpiration), the rest is the response.
+type DiskCache struct {
+	Dir string
+}
+
+//NewDiskCache creates dir if needed and returns a DiskCache on it.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{Dir: dir}, nil
+}
+
+func (c *DiskCache) path(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".cache")
+}
+
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	content, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	i := bytes.IndexByte(content, '\n')
+	if i < 0 {
+		return nil, false
+	}
+	expires, err := strconv.ParseInt(string(content[:i]), 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	//expired files are left in place, the next Set overwrites them
+	if expires != 0 && time.Now().UnixNano() > expires {
+		return nil, false
+	}
+	return content[i+1:], true
+}
+
+//Set writes to a temporary file and renames it, so concurrent Gets never see a partial response.
+//Write errors are ignored, the response is simply not cached.
+func (c *DiskCache) Set(key string, value []byte, ttl time.Duration) {
+	var expires int64
+	if expiry := expiryOf(ttl); !expiry.IsZero() {
+		expires = expiry.UnixNano()
+	}
+
+	tmpfile, err := ioutil.TempFile(c.Dir, "tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmpfile.WriteString(strconv.FormatInt(expires, 10) + "\n")
+	if err == nil {
+		_, err = tmpfile.Write(value)
+	}
+	if cerr := tmpfile.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmpfile.Name())
+		return
+	}
+	if err := os.Rename(tmpfile.Name(), c.path(key)); err != nil {
+		os.Remove(tmpfile.Name())
+	}
+}
@@ -0,0 +1,94 @@
+package dota2
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", []byte("1"), NoExpiration)
+	cache.Set("b", []byte("2"), time.Millisecond)
+	cache.Get("a")
+	cache.Set("c", []byte("3"), time.Hour) //evicts b, the least recently used
+
+	if _, found := cache.Get("b"); found {
+		t.Errorf("b should have been evicted\n")
+	}
+	if v, found := cache.Get("a"); !found || string(v) != "1" {
+		t.Errorf("a should be cached, Got:%s, %v\n", v, found)
+	}
+
+	cache.Set("d", []byte("4"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, found := cache.Get("d"); found {
+		t.Errorf("d should have expired\n")
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDiskCache failed, %v\n", err)
+	}
+
+	cache.Set("GetMatchDetails?match_id=4080856812", []byte(`{"result":{}}`), NoExpiration)
+	cache.Set("GetLiveLeagueGames?", []byte(`{"result":{}}`), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if v, found := cache.Get("GetMatchDetails?match_id=4080856812"); !found || string(v) != `{"result":{}}` {
+		t.Errorf("Match details should never expire, Got:%s, %v\n", v, found)
+	}
+	if _, found := cache.Get("GetLiveLeagueGames?"); found {
+		t.Errorf("Live games should have expired\n")
+	}
+
+	//an expired entry is simply overwritten
+	cache.Set("GetLiveLeagueGames?", []byte(`{"result":{"games":[]}}`), time.Hour)
+	if v, found := cache.Get("GetLiveLeagueGames?"); !found || string(v) != `{"result":{"games":[]}}` {
+		t.Errorf("Fresh live games should replace the expired ones, Got:%s, %v\n", v, found)
+	}
+
+	//the defaults are copies, changing them doesn't leak into other instances
+	DefaultCacheTTLs()["GetMatchDetails"] = time.Second
+	if DefaultCacheTTLs()["GetMatchDetails"] != NoExpiration {
+		t.Errorf("DefaultCacheTTLs should return a new map on every call\n")
+	}
+}
+
+func TestGetWithCache(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Query().Get("match_id") == "1" {
+			w.Write([]byte(`{"result":{"error":"Match ID not found"}}`))
+			return
+		}
+		w.Write([]byte(`{"result":{"match_id":4080856812,"radiant_win":true}}`))
+	}))
+	defer srv.Close()
+
+	dapi := New(WithBaseURL(srv.URL), WithCache(NewMemoryCache(0)))
+	for _, key := range []string{"key1", "key2"} {
+		dapi.SetApiKey(key) //the api key isn't part of the cache key
+		md, err := dapi.GetMatchDetails("4080856812")
+		if err != nil || md.MatchID != 4080856812 {
+			t.Fatalf("GetMatchDetails failed, Got:%+v, %v\n", md, err)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("Second GetMatchDetails should be served from cache, requests:%d\n", n)
+	}
+
+	//failures and endpoints without ttl are never cached
+	dapi.GetMatchDetails("1")
+	dapi.GetMatchDetails("1")
+	dapi.GetServerInfo()
+	dapi.GetServerInfo()
+	if n := atomic.LoadInt32(&requests); n != 5 {
+		t.Errorf("Failed results and GetServerInfo shouldn't be cached, requests:%d\n", n)
+	}
+}
@@ -1,9 +1,45 @@
 package dota2
 
 import (
-	"net/http"
+	"context"
 )
 
-type Client struct {
-	Client *http.Client
+//Dota2API describes all requests of Dota2api, depend on it instead of *Dota2api so that
+//Fake can be used in unit tests.
+type Dota2API interface {
+	GetMatchHistory(account SteamID) (MatchHistory, error)
+	GetMatchHistoryContext(ctx context.Context, account SteamID) (MatchHistory, error)
+	GetMatchDetails(matchid string) (MatchDetail, error)
+	GetMatchDetailsContext(ctx context.Context, matchid string) (MatchDetail, error)
+	GetMatchHistoryBySeqNum(startSeq int64, count int) (MatchHistoryBySeqNum, error)
+	GetMatchHistoryBySeqNumContext(ctx context.Context, startSeq int64, count int) (MatchHistoryBySeqNum, error)
+	GetLeagueListing() (LeagueList, error)
+	GetLeagueListingContext(ctx context.Context) (LeagueList, error)
+	GetTeamInfoByTeamID(startAtTeamID int64, teamsRequested int) (TeamInfoList, error)
+	GetTeamInfoByTeamIDContext(ctx context.Context, startAtTeamID int64, teamsRequested int) (TeamInfoList, error)
+	GetPlayerSummaries(steamids ...SteamID) (PlayerSummaryList, error)
+	GetPlayerSummariesContext(ctx context.Context, steamids ...SteamID) (PlayerSummaryList, error)
+	GetFriendList(steamid SteamID, relationship string) ([]FriendInfo, error)
+	GetFriendListContext(ctx context.Context, steamid SteamID, relationship string) ([]FriendInfo, error)
+	GetServerInfo() (ServerInfo, error)
+	GetServerInfoContext(ctx context.Context) (ServerInfo, error)
+	GetHeroes(language string) (HeroList, error)
+	GetHeroesContext(ctx context.Context, language string) (HeroList, error)
+	GetGameItems(language string) (ItemList, error)
+	GetGameItemsContext(ctx context.Context, language string) (ItemList, error)
+	GetTournamentPrizePool(leagueID int) (PrizePool, error)
+	GetTournamentPrizePoolContext(ctx context.Context, leagueID int) (PrizePool, error)
+	GetLiveLeagueGames() (LeagueGames, error)
+	GetLiveLeagueGamesContext(ctx context.Context) (LeagueGames, error)
+	GetTopLiveGame(partner int) (TopLiveGames, error)
+	GetTopLiveGameContext(ctx context.Context, partner int) (TopLiveGames, error)
+	GetHeroRegistry(language string) (*HeroRegistry, error)
+	GetHeroRegistryContext(ctx context.Context, language string) (*HeroRegistry, error)
+	GetItemRegistry(language string) (*ItemRegistry, error)
+	GetItemRegistryContext(ctx context.Context, language string) (*ItemRegistry, error)
 }
+
+var (
+	_ Dota2API = (*Dota2api)(nil)
+	_ Dota2API = (*Fake)(nil)
+)
@@ -0,0 +1,229 @@
+package dota2
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_CRAWLER_BATCHSIZE = 100
+	MAX_CRAWLER_BATCHSIZE     = 100 //Steam caps matches_requested at 100
+	DEFAULT_CRAWLER_INTERVAL  = 10 * time.Second
+	DEFAULT_CHECKPOINT_FILE   = "dota2_seqnum.checkpoint"
+)
+
+//Checkpoint persists the last processed match_seq_num of a SeqCrawler,
+//so that the crawler can resume from where it stopped after a restart or a crash.
+type Checkpoint interface {
+	//Load returns the last saved match_seq_num, or 0 when nothing has been saved yet.
+	Load() (int64, error)
+	//Save records seqnum as the last processed match_seq_num.
+	Save(seqnum int64) error
+}
+
+//FileCheckpoint is the default Checkpoint which keeps the match_seq_num as text in a single file.
+type FileCheckpoint struct {
+	Path string
+}
+
+func NewFileCheckpoint(path string) *FileCheckpoint {
+	return &FileCheckpoint{Path: path}
+}
+
+//Load reads the match_seq_num from file, a missing file means there is no checkpoint yet.
+func (fc *FileCheckpoint) Load() (int64, error) {
+	bseqnum, err := ioutil.ReadFile(fc.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	seqnum, err := strconv.ParseInt(strings.TrimSpace(string(bseqnum)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkpoint file %s: %v", fc.Path, err)
+	}
+	return seqnum, nil
+}
+
+//Save writes the match_seq_num to a temporary file first and then renames it,
+//so a crash during writing never leaves a truncated checkpoint behind.
+func (fc *FileCheckpoint) Save(seqnum int64) error {
+	tmpfile, err := ioutil.TempFile(filepath.Dir(fc.Path), filepath.Base(fc.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmpfile.WriteString(strconv.FormatInt(seqnum, 10))
+	if cerr := tmpfile.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmpfile.Name())
+		return err
+	}
+
+	return os.Rename(tmpfile.Name(), fc.Path)
+}
+
+//SeqCrawler walks match_seq_num forward through GetMatchHistoryBySeqNum and emits every match it meets.
+//The consumer calls Commit after a match has been processed, and the highest committed match_seq_num
+//is saved to the Checkpoint once per batch, so matches are delivered at least once across restarts.
+type SeqCrawler struct {
+	StartSeq  int64         //match_seq_num to start with when the checkpoint is empty
+	BatchSize int           //matches requested per API call, at most MAX_CRAWLER_BATCHSIZE
+	Interval  time.Duration //waiting time after catching up with the latest match or after a failed request
+	OnError   func(error)   //optional, called with every failed request before it's retried
+
+	api        Dota2API
+	checkpoint Checkpoint
+
+	mu        sync.Mutex
+	committed int64 //highest match_seq_num passed to Commit
+	saved     int64 //highest match_seq_num saved to checkpoint
+}
+
+//NewSeqCrawler creates a crawler on top of api, checkpoint defaults to a FileCheckpoint
+//in the working directory when nil.
+func NewSeqCrawler(api Dota2API, checkpoint Checkpoint) *SeqCrawler {
+	if checkpoint == nil {
+		checkpoint = NewFileCheckpoint(DEFAULT_CHECKPOINT_FILE)
+	}
+
+	return &SeqCrawler{
+		BatchSize:  DEFAULT_CRAWLER_BATCHSIZE,
+		Interval:   DEFAULT_CRAWLER_INTERVAL,
+		api:        api,
+		checkpoint: checkpoint,
+	}
+}
+
+//Commit marks the match with seqnum and all matches before it as processed.
+//It's safe to call from the consumer goroutine while Run is running.
+func (c *SeqCrawler) Commit(seqnum int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if seqnum > c.committed {
+		c.committed = seqnum
+	}
+}
+
+//Run sends matches to the channel in sequence order until ctx is done or the checkpoint can't be saved.
+//Failed requests are retried after Interval, so Run only returns ctx.Err() or a checkpoint error,
+//besides ErrInvalidParameter right away when BatchSize <= 0.
+//Matches which were sent but not committed when Run returns are sent again by the next Run.
+//example:
+//	matches := make(chan MatchDetail)
+//	go crawler.Run(ctx, matches)
+//	for match := range matches {
+//		...
+//		crawler.Commit(match.MatchSeqNum)
+//	}
+//The channel is closed when Run returns.
+func (c *SeqCrawler) Run(ctx context.Context, matches chan<- MatchDetail) (err error) {
+	defer close(matches)
+
+	batchsize := c.BatchSize
+	if batchsize <= 0 {
+		return fmt.Errorf("%w: BatchSize must be greater than 0, got %d", ErrInvalidParameter, batchsize)
+	}
+	if batchsize > MAX_CRAWLER_BATCHSIZE {
+		batchsize = MAX_CRAWLER_BATCHSIZE
+	}
+
+	lastseq, err := c.checkpoint.Load()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.committed, c.saved = lastseq, lastseq
+	c.mu.Unlock()
+	defer func() {
+		if serr := c.save(); serr != nil {
+			err = serr
+		}
+	}()
+
+	nextseq := c.StartSeq
+	if lastseq > 0 {
+		nextseq = lastseq + 1
+	}
+
+	for {
+		if err := c.save(); err != nil {
+			return err
+		}
+
+		mhseq, err := c.api.GetMatchHistoryBySeqNumContext(ctx, nextseq, batchsize)
+		if err != nil {
+			if c.OnError != nil {
+				c.OnError(err)
+			}
+			if err := c.wait(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, match := range mhseq.Matches {
+			if match.MatchSeqNum < nextseq {
+				continue
+			}
+
+			select {
+			case matches <- match:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			nextseq = match.MatchSeqNum + 1
+		}
+
+		//a partial batch means we have reached the newest recorded match
+		if len(mhseq.Matches) < batchsize {
+			if err := c.wait(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+//save writes the highest committed match_seq_num to the checkpoint if it has changed since the last save.
+func (c *SeqCrawler) save() error {
+	c.mu.Lock()
+	committed, saved := c.committed, c.saved
+	c.mu.Unlock()
+
+	if committed <= saved {
+		return nil
+	}
+	if err := c.checkpoint.Save(committed); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.saved = committed
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *SeqCrawler) wait(ctx context.Context) error {
+	timer := time.NewTimer(c.Interval)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,179 @@
+package dota2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+//newSeqNumServer serves GetMatchHistoryBySequenceNum for match_seq_num 1..lastseq, at most 100 per request like Steam.
+func newSeqNumServer(lastseq int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.ParseInt(r.URL.Query().Get("start_at_match_seq_num"), 10, 64)
+		count, _ := strconv.Atoi(r.URL.Query().Get("matches_requested"))
+		if count > 100 {
+			count = 100
+		}
+
+		fmt.Fprint(w, `{"result":{"status":1,"matches":[`)
+		for seq := start; seq <= lastseq && seq < start+int64(count); seq++ {
+			if seq != start {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"match_id":%d,"match_seq_num":%d}`, seq+1000, seq)
+		}
+		fmt.Fprint(w, `]}}`)
+	}))
+}
+
+func TestFileCheckpoint(t *testing.T) {
+	cp := NewFileCheckpoint(filepath.Join(t.TempDir(), "seqnum"))
+
+	seqnum, err := cp.Load()
+	if err != nil || seqnum != 0 {
+		t.Errorf("Empty checkpoint should load 0 without error, Got:%d, %v\n", seqnum, err)
+	}
+
+	if err := cp.Save(4080856812); err != nil {
+		t.Fatalf("Save checkpoint failed, %v\n", err)
+	}
+	seqnum, err = cp.Load()
+	if err != nil || seqnum != 4080856812 {
+		t.Errorf("Checkpoint should load 4080856812, Got:%d, %v\n", seqnum, err)
+	}
+}
+
+func TestSeqCrawlerResume(t *testing.T) {
+	srv := newSeqNumServer(7)
+	defer srv.Close()
+
+	cp := NewFileCheckpoint(filepath.Join(t.TempDir(), "seqnum"))
+
+	//first run stops after 4 matches, the second one must continue with the 5th.
+	crawl := func(n int) []int64 {
+		crawler := NewSeqCrawler(New(WithBaseURL(srv.URL)), cp)
+		crawler.StartSeq = 1
+		crawler.BatchSize = 3
+		crawler.Interval = time.Millisecond
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		matches := make(chan MatchDetail)
+		done := make(chan error)
+		go func() { done <- crawler.Run(ctx, matches) }()
+
+		var seqnums []int64
+		for match := range matches {
+			seqnums = append(seqnums, match.MatchSeqNum)
+			crawler.Commit(match.MatchSeqNum)
+			if len(seqnums) == n {
+				cancel()
+				break
+			}
+		}
+		if err := <-done; err != context.Canceled {
+			t.Errorf("Run should return context.Canceled, Got:%v\n", err)
+		}
+		return seqnums
+	}
+
+	first := crawl(4)
+	if fmt.Sprint(first) != "[1 2 3 4]" {
+		t.Errorf("First run got match_seq_num %v, Expected:[1 2 3 4]\n", first)
+	}
+
+	second := crawl(3)
+	if fmt.Sprint(second) != "[5 6 7]" {
+		t.Errorf("Resumed run got match_seq_num %v, Expected:[5 6 7]\n", second)
+	}
+}
+
+//countingCheckpoint keeps the match_seq_num in memory and counts the saves.
+type countingCheckpoint struct {
+	seqnum int64
+	saves  int
+}
+
+func (cp *countingCheckpoint) Load() (int64, error) { return cp.seqnum, nil }
+
+func (cp *countingCheckpoint) Save(seqnum int64) error {
+	cp.seqnum = seqnum
+	cp.saves++
+	return nil
+}
+
+func TestSeqCrawlerCommit(t *testing.T) {
+	srv := newSeqNumServer(7)
+	defer srv.Close()
+
+	cp := &countingCheckpoint{}
+	crawler := NewSeqCrawler(New(WithBaseURL(srv.URL)), cp)
+	crawler.StartSeq = 1
+	crawler.BatchSize = 3
+	crawler.Interval = time.Millisecond
+
+	//the consumer receives 5 matches but only finishes processing the first 4
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	matches := make(chan MatchDetail)
+	done := make(chan error)
+	go func() { done <- crawler.Run(ctx, matches) }()
+
+	received := 0
+	for match := range matches {
+		received++
+		if received == 5 {
+			cancel()
+			break
+		}
+		crawler.Commit(match.MatchSeqNum)
+	}
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run should return context.Canceled, Got:%v\n", err)
+	}
+
+	if cp.seqnum != 4 {
+		t.Errorf("Checkpoint should hold the last committed match_seq_num 4, Got:%d\n", cp.seqnum)
+	}
+	//once after the first batch, once when Run returns
+	if cp.saves > 2 {
+		t.Errorf("Checkpoint should be saved once per batch, Got %d saves\n", cp.saves)
+	}
+}
+
+func TestSeqCrawlerBatchSize(t *testing.T) {
+	srv := newSeqNumServer(250)
+	defer srv.Close()
+
+	crawler := NewSeqCrawler(New(WithBaseURL(srv.URL)), &countingCheckpoint{})
+	crawler.StartSeq = 1
+	crawler.BatchSize = 0
+	if err := crawler.Run(context.Background(), make(chan MatchDetail)); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("BatchSize 0 should fail with ErrInvalidParameter, Got:%v\n", err)
+	}
+
+	//full batches of 100 mustn't be mistaken for catching up and wait an hour
+	crawler.BatchSize = 500
+	crawler.Interval = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	matches := make(chan MatchDetail)
+	go crawler.Run(ctx, matches)
+
+	received := 0
+	for range matches {
+		received++
+		if received == 250 {
+			break
+		}
+	}
+	if received != 250 {
+		t.Errorf("Expected 250 matches without waiting for Interval, Got:%d\n", received)
+	}
+}
@@ -0,0 +1,86 @@
+package dota2test
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	srv := NewServer()
+	dir := t.TempDir()
+
+	recorder := NewRecorder(dir, ModeRecord)
+	client := recorder.Client()
+	resp, err := client.Get(srv.URL + "/IEconDOTA2_570/GetTournamentPrizePool/v1/?leagueid=9870&key=secret")
+	if err != nil {
+		t.Fatalf("Recording failed, %v\n", err)
+	}
+	recorded, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	srv.Close()
+
+	//replay without the server, parameters in another order and another key
+	recorder.Mode = ModeReplay
+	resp, err = client.Get(srv.URL + "/IEconDOTA2_570/GetTournamentPrizePool/v1/?key=other&leagueid=9870")
+	if err != nil {
+		t.Fatalf("Replaying failed, %v\n", err)
+	}
+	replayed, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(replayed) != string(recorded) || len(recorded) == 0 {
+		t.Errorf("Replayed body differs, recorded:%s, replayed:%s\n", recorded, replayed)
+	}
+
+	_, err = client.Get(srv.URL + "/IEconDOTA2_570/GetTournamentPrizePool/v1/?leagueid=1")
+	if !errors.Is(err, ErrCassetteNotFound) {
+		t.Errorf("Unrecorded request should fail with ErrCassetteNotFound, Got:%v\n", err)
+	}
+
+	files, _ := ioutil.ReadDir(dir)
+	for _, file := range files {
+		content, _ := ioutil.ReadFile(dir + "/" + file.Name())
+		if string(content) != string(recorded) {
+			t.Errorf("Cassette %s should contain the raw body\n", file.Name())
+		}
+	}
+}
+
+func TestLoadCassettesAsFixtures(t *testing.T) {
+	dir := t.TempDir()
+	srv := NewServer()
+	srv.SetFixture("GetTournamentPrizePool", []byte(`{"result":{"prize_pool":40000000,"league_id":10749,"status":200}}`))
+	recorder := NewRecorder(dir, ModeRecord)
+	resp, err := recorder.Client().Get(srv.URL + "/IEconDOTA2_570/GetTournamentPrizePool/v1/?leagueid=10749&key=secret")
+	if err != nil {
+		t.Fatalf("Recording failed, %v\n", err)
+	}
+	resp.Body.Close()
+	srv.Close()
+
+	//a fresh server serves the recorded response instead of its built-in fixture
+	srv = NewServer()
+	defer srv.Close()
+	if err := srv.LoadFixtures(dir); err != nil {
+		t.Fatalf("LoadFixtures failed, %v\n", err)
+	}
+	resp, err = srv.Client().Get(srv.URL + "/IEconDOTA2_570/GetTournamentPrizePool/v1/?leagueid=10749")
+	if err != nil {
+		t.Fatalf("Request failed, %v\n", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != `{"result":{"prize_pool":40000000,"league_id":10749,"status":200}}` {
+		t.Errorf("Cassette should be served as fixture of GetTournamentPrizePool, Got:%s\n", body)
+	}
+
+	for file, method := range map[string]string{
+		"GetLiveLeagueGames.json":                                "GetLiveLeagueGames",
+		"IDOTA2Match_570_GetLiveLeagueGames_v0001.json":          "GetLiveLeagueGames",
+		"IDOTA2Match_570_GetMatchDetails_v001_0123456789ab.json": "GetMatchDetails",
+	} {
+		if got := fixtureMethod(file); got != method {
+			t.Errorf("fixtureMethod(%s) should be %s, Got:%s\n", file, method, got)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package dota2test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, url string) (*http.Response, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("Request %s failed, %v\n", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestServer(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	resp, body := get(t, srv.URL+"/IEconDOTA2_570/GetTournamentPrizePool/v1/?key=abc&leagueid=9870")
+	if resp.StatusCode != http.StatusOK || body == "" {
+		t.Errorf("Fixture should be served, Got:%d %s\n", resp.StatusCode, body)
+	}
+
+	resp, _ = get(t, srv.URL+"/IUnknown/GetSomething/v1/")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Unknown interface should be 404, Got:%d\n", resp.StatusCode)
+	}
+
+	srv.RateLimit("GetMatchDetails", 2*time.Second, 1)
+	resp, _ = get(t, srv.URL+"/IDOTA2Match_570/GetMatchDetails/v001/?match_id=4080856812")
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "2" {
+		t.Errorf("Should be rate limited once, Got:%d Retry-After:%s\n", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	resp, _ = get(t, srv.URL+"/IDOTA2Match_570/GetMatchDetails/v001/?match_id=4080856812")
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Fault should be gone after Times requests, Got:%d\n", resp.StatusCode)
+	}
+
+	srv.RequireKey("abc")
+	resp, _ = get(t, srv.URL+"/ISteamUser/GetPlayerSummaries/v0002/?key=wrong")
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Wrong key should be 403, Got:%d\n", resp.StatusCode)
+	}
+
+	requests := srv.Requests()
+	//requests to unknown interfaces are not recorded
+	if len(requests) != 4 || requests[1].Method != "GetMatchDetails" || requests[3].Interface != "ISteamUser" {
+		t.Fatalf("Got unexpected requests:%+v\n", requests)
+	}
+	if requests[0].Query.Get("leagueid") != "9870" || requests[3].Query.Get("key") != "wrong" {
+		t.Errorf("Query should be recorded, Got:%v %v\n", requests[0].Query, requests[3].Query)
+	}
+}
@@ -0,0 +1,97 @@
+package dota2
+
+import (
+	"strings"
+)
+
+//Endpoint is a method of Steam Web API, its url is {base_url}/{interface}/{method}/{version}/
+type Endpoint struct {
+	Interface string //eg: IDOTA2Match_570
+	Method    string //eg: GetMatchDetails
+	Version   string //eg: v001
+}
+
+//Path returns the url of the endpoint without base url, eg: IDOTA2Match_570/GetMatchDetails/v001/
+func (e Endpoint) Path() string {
+	return e.Interface + "/" + e.Method + "/" + e.Version + "/"
+}
+
+//defaultEndpoints returns a new endpoint table, every Dota2api owns a copy so that they can be changed separately.
+//The keys are the same as URLMap.
+func defaultEndpoints() map[string]Endpoint {
+	return map[string]Endpoint{
+		"GetMatchHistory":         {"IDOTA2Match_570", "GetMatchHistory", "v001"},
+		"GetMatchHistoryBySeqNum": {"IDOTA2Match_570", "GetMatchHistoryBySequenceNum", "v0001"},
+		"GetMatchDetails":         {"IDOTA2Match_570", "GetMatchDetails", "v001"},
+		"GetLeagueListing":        {"IDOTA2Match_205790", "GetLeagueListing", "v0001"},
+		"GetLiveLeagueGames":      {"IDOTA2Match_570", "GetLiveLeagueGames", "v0001"},
+		"GetTeamInfoByTeamId":     {"IDOTA2Match_570", "GetTeamInfoByTeamID", "v001"},
+		"GetPlayerSummaries":      {"ISteamUser", "GetPlayerSummaries", "v0002"},
+		"GetFriendList":           {"ISteamUser", "GetFriendList", "v0001"},
+		"GetServerInfo":           {"ISteamWebAPIUtil", "GetServerInfo", "v0001"},
+		"GetHeroes":               {"IEconDOTA2_570", "GetHeroes", "v0001"},
+		"GetGameItems":            {"IEconDOTA2_570", "GetGameItems", "v0001"},
+		"GetTournamentPrizePool":  {"IEconDOTA2_570", "GetTournamentPrizePool", "v1"},
+		"GetTopLiveGame":          {"IDOTA2Match_570", "GetTopLiveGame", "v1"},
+	}
+}
+
+//Endpoint returns the endpoint of d by name, eg: "GetMatchDetails".
+func (d *Dota2api) Endpoint(name string) (Endpoint, bool) {
+	ep, found := d.endpoints[name]
+	return ep, found
+}
+
+//SetEndpoint adds or replaces the endpoint name of d, other Dota2api instances are not affected.
+//Like the other setters, it should be called before d is used by several goroutines.
+func (d *Dota2api) SetEndpoint(name string, ep Endpoint) {
+	d.endpoints[name] = ep
+}
+
+//SetEndpointVersion changes the version of a single endpoint when Valve rolls it, eg:
+//	SetEndpointVersion("GetMatchDetails", "v002")
+func (d *Dota2api) SetEndpointVersion(name string, version string) error {
+	ep, found := d.endpoints[name]
+	if !found {
+		return URLMapError
+	}
+	ep.Version = version
+	d.endpoints[name] = ep
+	return nil
+}
+
+//WithEndpointVersion is the same as SetEndpointVersion, unknown names are ignored.
+func WithEndpointVersion(name string, version string) Option {
+	return func(d *Dota2api) {
+		d.SetEndpointVersion(name, version)
+	}
+}
+
+//endpointURL returns the full url of endpoint name, with the base url set by WithBaseURL.
+func (d *Dota2api) endpointURL(name string) (string, bool) {
+	ep, found := d.endpoints[name]
+	if !found {
+		return "", false
+	}
+	return d.baseURL + ep.Path(), true
+}
+
+//endpointName finds the endpoint which rawurl was built from by its interface and method,
+//so it ignores the base url and the version.
+func (d *Dota2api) endpointName(rawurl string) string {
+	if i := strings.IndexByte(rawurl, '?'); i >= 0 {
+		rawurl = rawurl[:i]
+	}
+	segments := strings.Split(strings.TrimSuffix(rawurl, "/"), "/")
+	if len(segments) < 3 {
+		return ""
+	}
+	iface, method := segments[len(segments)-3], segments[len(segments)-2]
+
+	for name, ep := range d.endpoints {
+		if ep.Interface == iface && ep.Method == method {
+			return name
+		}
+	}
+	return ""
+}
@@ -1 +1,184 @@
 package dota2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	APIERROR_BODY_LIMIT = 512 //max length of the response body kept in APIError
+	REDACTED_KEY        = "REDACTED"
+)
+
+//sentinel errors matched by APIError, use them with errors.Is:
+//	if errors.Is(err, dota2.ErrRateLimited) { ... }
+var (
+	ErrUnauthorized       = errors.New("Unauthorized by Steam Web API, the api key may be invalid")
+	ErrRateLimited        = errors.New("Too many requests to Steam Web API")
+	ErrServiceUnavailable = errors.New("Steam Web API is unavailable")
+	ErrEmptyResponse      = errors.New("Steam Web API returned an empty response")
+)
+
+//sentinel errors matched by StatusError
+var (
+	ErrPrivateProfile   = errors.New("The player hasn't allowed to expose the match history")
+	ErrInvalidParameter = errors.New("Invalid parameter for Steam Web API")
+	ErrMatchNotFound    = errors.New("Match not found, it may not exist or not be finished yet")
+)
+
+//APIError is returned when Steam Web API answers with a non-2xx HTTP status.
+type APIError struct {
+	StatusCode int           //HTTP status code, eg: 403
+	Endpoint   string        //name of the endpoint, eg: GetMatchDetails, empty for unknown urls
+	URL        string        //request url with the api key redacted
+	Body       string        //beginning of the response body, usually an html error page
+	RetryAfter time.Duration //waiting time requested by the Retry-After header, 0 if absent
+}
+
+func (e *APIError) Error() string {
+	endpoint := e.Endpoint
+	if endpoint == "" {
+		endpoint = "Request"
+	}
+	return fmt.Sprintf("%s failed with HTTP %d %s, url=%s, body=%q",
+		endpoint, e.StatusCode, http.StatusText(e.StatusCode), e.URL, e.Body)
+}
+
+//Is reports whether the HTTP status matches one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServiceUnavailable:
+		return e.StatusCode == http.StatusBadGateway || e.StatusCode == http.StatusServiceUnavailable ||
+			e.StatusCode == http.StatusGatewayTimeout
+	}
+	return false
+}
+
+//StatusError is returned when the HTTP request succeeded but the result reports a failure,
+//either by its "status" or by an "error" message like {"result":{"error":"Match ID not found"}}.
+//The result itself is still returned along with the error, so the raw status stays accessible.
+type StatusError struct {
+	Endpoint     string //name of the endpoint, eg: GetMatchHistory
+	Status       int    //raw status of the result, see consts RESULTSTATUS_xx, 0 for an "error" message
+	StatusDetail string //message explaining the failure, not provided by every endpoint
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s failed, status=%d, detail=%s", e.Endpoint, e.Status, e.StatusDetail)
+}
+
+//Is maps known status values to the sentinel errors.
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrPrivateProfile:
+		return e.Status == RESULTSTATUS_ACCESS_DENIED
+	case ErrInvalidParameter:
+		return e.Status == RESULTSTATUS_INVALID_PARAMETER
+	case ErrMatchNotFound:
+		return e.Status == 0 && e.StatusDetail == RESULTERROR_MATCH_NOT_FOUND
+	}
+	return false
+}
+
+//checkResult decodes the "status", "statusDetail" and "error" of {"result":{...}} in bresp,
+//and turns a failure into *StatusError. Responses without a result object are successes.
+func checkResult(endpoint string, bresp []byte) error {
+	var probe struct {
+		Result struct {
+			Status       int    `json:"status"`
+			StatusDetail string `json:"statusDetail"`
+			Error        string `json:"error"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(bresp, &probe); err != nil {
+		return err
+	}
+	if probe.Result.Error != "" {
+		return &StatusError{Endpoint: endpoint, StatusDetail: probe.Result.Error}
+	}
+	return checkStatus(endpoint, probe.Result.Status, probe.Result.StatusDetail)
+}
+
+//checkStatus turns a failed status of endpoint into *StatusError.
+//1 and 200 mean success depending on the interface, 0 means the result has no status at all.
+func checkStatus(endpoint string, status int, detail string) error {
+	switch status {
+	case 0, RESULTSTATUS_SUCCESS, RESULTSTATUS_OK:
+		return nil
+	}
+	return &StatusError{
+		Endpoint:     endpoint,
+		Status:       status,
+		StatusDetail: detail,
+	}
+}
+
+func newAPIError(endpoint string, resp *http.Response, rawurl string, bresp []byte) *APIError {
+	body := strings.TrimSpace(string(bresp))
+	if len(body) > APIERROR_BODY_LIMIT {
+		body = body[:APIERROR_BODY_LIMIT]
+	}
+
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Endpoint:   endpoint,
+		URL:        redactURL(rawurl),
+		Body:       body,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+}
+
+//parseRetryAfter accepts both forms of Retry-After, delay in seconds or an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+var keyParamPattern = regexp.MustCompile(`([?&]key=)[^&#]*`)
+
+//redactURL replaces the api key in rawurl, so the url can be shown in errors and logs.
+func redactURL(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		//the key must not leak even if the url is malformed
+		return keyParamPattern.ReplaceAllString(rawurl, "${1}"+REDACTED_KEY)
+	}
+
+	query := u.Query()
+	if query.Get("key") == "" {
+		return rawurl
+	}
+	query.Set("key", REDACTED_KEY)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+//redactError hides the api key in the url of errors returned by http.Client.
+func redactError(err error) error {
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		uerr.URL = redactURL(uerr.URL)
+	}
+	return err
+}
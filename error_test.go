@@ -0,0 +1,113 @@
+package dota2
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	status := http.StatusForbidden
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte("<html><body>Access is denied.</body></html>"))
+	}))
+	defer srv.Close()
+
+	dapi := New(WithBaseURL(srv.URL), WithAPIKey("E09635A9F555CE8F0B0CCEECE8E40434"))
+	_, err := dapi.GetMatchDetails("4080856812")
+
+	var apierr *APIError
+	if !errors.As(err, &apierr) {
+		t.Fatalf("Expected *APIError, Got:%v\n", err)
+	}
+	if apierr.StatusCode != http.StatusForbidden || apierr.Endpoint != "GetMatchDetails" {
+		t.Errorf("Got unexpected APIError:%+v\n", apierr)
+	}
+	if strings.Contains(apierr.Error(), "E09635A9F555CE8F0B0CCEECE8E40434") {
+		t.Errorf("API key should be redacted, Got:%s\n", apierr.Error())
+	}
+	if apierr.Body != "<html><body>Access is denied.</body></html>" {
+		t.Errorf("Got unexpected body:%s\n", apierr.Body)
+	}
+	if !errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrRateLimited) {
+		t.Errorf("403 should only match ErrUnauthorized, Got:%v\n", err)
+	}
+
+	for code, target := range map[int]error{
+		http.StatusTooManyRequests:    ErrRateLimited,
+		http.StatusServiceUnavailable: ErrServiceUnavailable,
+	} {
+		status = code
+		if _, err := dapi.GetMatchDetails("4080856812"); !errors.Is(err, target) {
+			t.Errorf("HTTP %d should match %v, Got:%v\n", code, target, err)
+		}
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":{"status":15,"statusDetail":"Cannot get match history for a user that hasn't allowed it"}}`))
+	}))
+	defer srv.Close()
+
+	mh, err := New(WithBaseURL(srv.URL)).GetMatchHistory(NewSteamID(131900000))
+	if !errors.Is(err, ErrPrivateProfile) || errors.Is(err, ErrInvalidAccountID) {
+		t.Errorf("Status 15 should only match ErrPrivateProfile, Got:%v\n", err)
+	}
+
+	var staterr *StatusError
+	if !errors.As(err, &staterr) || staterr.Endpoint != "GetMatchHistory" || staterr.Status != 15 {
+		t.Errorf("Got unexpected StatusError:%+v\n", staterr)
+	}
+	if mh.Status != 15 {
+		t.Errorf("Raw status should be kept in the result, Got:%d\n", mh.Status)
+	}
+
+	if err := checkStatus("GetMatchHistoryBySeqNum", 8, "matches_requested must be greater than 0"); !errors.Is(err, ErrInvalidParameter) || errors.Is(err, ErrPrivateProfile) {
+		t.Errorf("Status 8 should match ErrInvalidParameter only, Got:%v\n", err)
+	}
+	for _, status := range []int{0, RESULTSTATUS_SUCCESS, RESULTSTATUS_OK} {
+		if err := checkStatus("GetHeroes", status, ""); err != nil {
+			t.Errorf("Status %d should be a success, Got:%v\n", status, err)
+		}
+	}
+}
+
+func TestMatchNotFound(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"result":{"error":"Match ID not found"}}`))
+	}))
+	defer srv.Close()
+
+	dapi := New(WithBaseURL(srv.URL), WithCache(NewMemoryCache(0)))
+	for i := 0; i < 2; i++ {
+		_, err := dapi.GetMatchDetails("4080856812")
+		if !errors.Is(err, ErrMatchNotFound) {
+			t.Fatalf("GetMatchDetails should fail with ErrMatchNotFound, Got:%v\n", err)
+		}
+		var staterr *StatusError
+		if !errors.As(err, &staterr) || staterr.Endpoint != "GetMatchDetails" || staterr.StatusDetail != RESULTERROR_MATCH_NOT_FOUND {
+			t.Errorf("Got unexpected StatusError:%+v\n", staterr)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Not found results must not be cached, requests:%d\n", requests)
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	for _, rawurl := range []string{
+		"http://api.steampowered.com/ISteamWebAPIUtil/GetServerInfo/v0001/?key=E09635A9F555CE8F0B0CCEECE8E40434&x=1",
+		"http://api.steampowered.com/%zz/?x=1&key=E09635A9F555CE8F0B0CCEECE8E40434", //malformed, url.Parse fails
+	} {
+		redacted := redactURL(rawurl)
+		if strings.Contains(redacted, "E09635A9F555CE8F0B0CCEECE8E40434") || !strings.Contains(redacted, "key="+REDACTED_KEY) {
+			t.Errorf("The api key should be redacted, Got:%s\n", redacted)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package dota2
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+//Fake is a Dota2API backed by canned responses instead of Steam Web API, for unit tests without network.
+//The responses are the raw json bodies Steam would return(eg: livinggames.json for GetLiveLeagueGames),
+//so they're decoded, validated and checked exactly like real ones.
+//example:
+//	fake := NewFake()
+//	fake.LoadResponse("GetLiveLeagueGames", "livinggames.json")
+//	var api Dota2API = fake
+type Fake struct {
+	*Dota2api
+
+	mu        sync.Mutex
+	responses map[string][]byte
+	errs      map[string]error
+	calls     []FakeCall
+}
+
+//FakeCall is a request received by Fake.
+type FakeCall struct {
+	Endpoint string     //eg: GetMatchDetails
+	Params   url.Values //query parameters without the api key
+}
+
+//NewFake creates a Fake without any response, requests to endpoints without a response fail with a 404 *APIError.
+//opts are applied to the embedded Dota2api, except WithHTTPClient which is overridden.
+func NewFake(opts ...Option) *Fake {
+	f := &Fake{
+		responses: make(map[string][]byte),
+		errs:      make(map[string]error),
+	}
+	opts = append(opts, WithHTTPClient(&http.Client{Transport: fakeTransport{f}}))
+	f.Dota2api = New(opts...)
+	return f
+}
+
+//SetResponse sets the json body returned for endpoint, eg: "GetMatchDetails".
+func (f *Fake) SetResponse(endpoint string, body []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[endpoint] = body
+}
+
+//LoadResponse sets the json body returned for endpoint from a file.
+func (f *Fake) LoadResponse(endpoint string, path string) error {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	f.SetResponse(endpoint, body)
+	return nil
+}
+
+//SetError makes requests to endpoint fail with err, as if the network failed. nil removes the error.
+func (f *Fake) SetError(endpoint string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err == nil {
+		delete(f.errs, endpoint)
+		return
+	}
+	f.errs[endpoint] = err
+}
+
+//Calls returns the requests received so far, in order.
+func (f *Fake) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeCall(nil), f.calls...)
+}
+
+//fakeTransport answers the requests of Fake's Dota2api with the canned responses.
+type fakeTransport struct {
+	f *Fake
+}
+
+func (t fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := t.f.endpointName(req.URL.String())
+	params := req.URL.Query()
+	params.Del("key")
+
+	t.f.mu.Lock()
+	t.f.calls = append(t.f.calls, FakeCall{Endpoint: endpoint, Params: params})
+	body, found := t.f.responses[endpoint]
+	err := t.f.errs[endpoint]
+	t.f.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Request:    req,
+	}
+	if !found {
+		resp.StatusCode = http.StatusNotFound
+		body = []byte(fmt.Sprintf("no canned response for %s", endpoint))
+	}
+	resp.Status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
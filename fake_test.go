@@ -0,0 +1,39 @@
+package dota2
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestFake(t *testing.T) {
+	fake := NewFake()
+	if err := fake.LoadResponse("GetLiveLeagueGames", "livinggames.json"); err != nil {
+		t.Fatalf("LoadResponse failed, %v\n", err)
+	}
+
+	var api Dota2API = fake
+	leaguegames, err := api.GetLiveLeagueGames()
+	if err != nil {
+		t.Fatalf("GetLiveLeagueGames failed, %v\n", err)
+	}
+	if len(leaguegames.Leagues) == 0 || leaguegames.Leagues[0].MatchID != 4216074905 {
+		t.Errorf("Canned livinggames.json not decoded, Got %d games\n", len(leaguegames.Leagues))
+	}
+
+	var apierr *APIError
+	if _, err := api.GetMatchDetails("4080856812"); !errors.As(err, &apierr) || apierr.StatusCode != http.StatusNotFound {
+		t.Errorf("Endpoint without response should fail with 404, Got:%v\n", err)
+	}
+
+	fake.SetError("GetHeroes", ErrServiceUnavailable)
+	if _, err := api.GetHeroes("en_us"); !errors.Is(err, ErrServiceUnavailable) {
+		t.Errorf("GetHeroes should fail with the injected error, Got:%v\n", err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 3 || calls[1].Endpoint != "GetMatchDetails" || calls[1].Params.Get("match_id") != "4080856812" ||
+		calls[2].Params.Get("language") != "en_us" {
+		t.Errorf("Got unexpected calls:%+v\n", calls)
+	}
+}
@@ -0,0 +1,88 @@
+package dota2
+
+import (
+	"context"
+	"strings"
+)
+
+//sizes of hero images on the cdn, use them with HeroRegistry.ImageURL
+const (
+	HEROIMAGE_SMALL    = "sb.png"   //59x33
+	HEROIMAGE_LARGE    = "lg.png"   //205x115
+	HEROIMAGE_FULL     = "full.png" //256x144
+	HEROIMAGE_VERTICAL = "vert.jpg" //234x272, portrait
+
+	HERO_NAME_PREFIX = "npc_dota_hero_"
+)
+
+//ShortName strips the "npc_dota_hero_" prefix from the internal name, eg: npc_dota_hero_antimage -> antimage.
+func (h Hero) ShortName() string {
+	return strings.TrimPrefix(h.Name, HERO_NAME_PREFIX)
+}
+
+//ImageURL returns the cdn url of the hero image, size is one of consts HEROIMAGE_xx.
+//eg: http://cdn.dota2.com/apps/dota2/images/heroes/antimage_lg.png
+func (h Hero) ImageURL(size string) string {
+	return BASE_HERO_IMAGES_URL + h.ShortName() + "_" + size
+}
+
+//HeroRegistry maps the numeric hero id used in MatchInfo, LeagueGame and TeamStatistic to the hero.
+//It's read-only after creation, so it's safe for concurrent use.
+type HeroRegistry struct {
+	heroes map[int]Hero
+}
+
+//NewHeroRegistry builds a registry from the result of GetHeroes.
+func NewHeroRegistry(heroes []Hero) *HeroRegistry {
+	reg := &HeroRegistry{
+		heroes: make(map[int]Hero, len(heroes)),
+	}
+	for _, hero := range heroes {
+		reg.heroes[hero.ID] = hero
+	}
+	return reg
+}
+
+//GetHeroRegistry requests GetHeroes with language and builds a HeroRegistry from the result.
+func (d *Dota2api) GetHeroRegistry(language string) (*HeroRegistry, error) {
+	return d.GetHeroRegistryContext(context.Background(), language)
+}
+
+//GetHeroRegistryContext is like GetHeroRegistry but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetHeroRegistryContext(ctx context.Context, language string) (*HeroRegistry, error) {
+	herolist, err := d.GetHeroesContext(ctx, language)
+	if err != nil {
+		return nil, err
+	}
+	return NewHeroRegistry(herolist.Heroes), nil
+}
+
+//Hero looks up the hero by id, the bool is false for unknown ids(eg: 0 for an unpicked slot).
+func (r *HeroRegistry) Hero(heroid int) (Hero, bool) {
+	hero, found := r.heroes[heroid]
+	return hero, found
+}
+
+//Name returns the internal name like npc_dota_hero_antimage, or "" for unknown ids.
+func (r *HeroRegistry) Name(heroid int) string {
+	return r.heroes[heroid].Name
+}
+
+//LocalizedName returns the name in the language GetHeroes was called with, or "" for unknown ids.
+func (r *HeroRegistry) LocalizedName(heroid int) string {
+	return r.heroes[heroid].LocalizedName
+}
+
+//ImageURL returns the cdn url of the hero image, or "" for unknown ids.
+func (r *HeroRegistry) ImageURL(heroid int, size string) string {
+	hero, found := r.heroes[heroid]
+	if !found {
+		return ""
+	}
+	return hero.ImageURL(size)
+}
+
+//Len returns the number of heroes in the registry.
+func (r *HeroRegistry) Len() int {
+	return len(r.heroes)
+}
@@ -0,0 +1,30 @@
+package dota2
+
+import (
+	"testing"
+)
+
+func TestHeroRegistry(t *testing.T) {
+	reg := NewHeroRegistry([]Hero{
+		{Name: "npc_dota_hero_antimage", ID: 1, LocalizedName: "Anti-Mage"},
+		{Name: "npc_dota_hero_shadow_shaman", ID: 27, LocalizedName: "Shadow Shaman"},
+	})
+
+	if reg.Len() != 2 {
+		t.Errorf("Expected 2 heroes, Got:%d\n", reg.Len())
+	}
+	if reg.LocalizedName(27) != "Shadow Shaman" || reg.Name(1) != "npc_dota_hero_antimage" {
+		t.Errorf("Got wrong names, 27->%s, 1->%s\n", reg.LocalizedName(27), reg.Name(1))
+	}
+	if _, found := reg.Hero(0); found {
+		t.Errorf("Hero id 0 should not be found.\n")
+	}
+
+	expected := "http://cdn.dota2.com/apps/dota2/images/heroes/shadow_shaman_lg.png"
+	if url := reg.ImageURL(27, HEROIMAGE_LARGE); url != expected {
+		t.Errorf("Got image url:%s, Expected:%s\n", url, expected)
+	}
+	if url := reg.ImageURL(999, HEROIMAGE_LARGE); url != "" {
+		t.Errorf("Unknown hero should have no image url, Got:%s\n", url)
+	}
+}
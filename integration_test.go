@@ -0,0 +1,83 @@
+package dota2
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Katsusan/go-dota2/dota2test"
+)
+
+//TestOfflineIntegration runs every API against dota2test.Server, so it works without network.
+func TestOfflineIntegration(t *testing.T) {
+	srv := dota2test.NewServer()
+	defer srv.Close()
+	srv.RequireKey("E09635A9F555CE8F0B0CCEECE8E40434")
+
+	dapi := New(WithBaseURL(srv.URL), WithAPIKey("E09635A9F555CE8F0B0CCEECE8E40434"))
+
+	mtd, err := dapi.GetMatchDetails("4080856812")
+	if err != nil || mtd.RadiantName != "PSG.LGD" || mtd.DireName != "OG" {
+		t.Errorf("GetMatchDetails got %s vs %s, %v\n", mtd.RadiantName, mtd.DireName, err)
+	}
+	if mh, err := dapi.GetMatchHistory(NewSteamID(131900000)); err != nil || len(mh.Matches) != 1 {
+		t.Errorf("GetMatchHistory got %d matches, %v\n", len(mh.Matches), err)
+	}
+	if mhseq, err := dapi.GetMatchHistoryBySeqNum(3546355370, 2); err != nil || len(mhseq.Matches) != 2 {
+		t.Errorf("GetMatchHistoryBySeqNum got %d matches, %v\n", len(mhseq.Matches), err)
+	}
+	if leagues, err := dapi.GetLeagueListing(); err != nil || len(leagues.Leagues) == 0 {
+		t.Errorf("GetLeagueListing got %d leagues, %v\n", len(leagues.Leagues), err)
+	}
+	if teams, err := dapi.GetTeamInfoByTeamID(2586976, 1); err != nil || len(teams.Teams) != 1 || len(teams.Teams[0].PlayerAccountIDs) != 5 {
+		t.Errorf("GetTeamInfoByTeamID got %+v, %v\n", teams, err)
+	}
+	if summaries, err := dapi.GetPlayerSummaries(76561198092165728); err != nil || len(summaries.PlayerSummary) != 1 {
+		t.Errorf("GetPlayerSummaries got %+v, %v\n", summaries, err)
+	}
+	if friends, err := dapi.GetFriendList(76561198092165728, "friend"); err != nil || len(friends) == 0 {
+		t.Errorf("GetFriendList got %+v, %v\n", friends, err)
+	}
+	if srvinfo, err := dapi.GetServerInfo(); err != nil || srvinfo.ServerTime == 0 {
+		t.Errorf("GetServerInfo got %+v, %v\n", srvinfo, err)
+	}
+	if heroes, err := dapi.GetHeroRegistry("en_us"); err != nil || heroes.LocalizedName(1) != "Anti-Mage" {
+		t.Errorf("GetHeroRegistry failed, %v\n", err)
+	}
+	if items, err := dapi.GetItemRegistry("en_us"); err != nil || items.Name(1) != "item_blink" {
+		t.Errorf("GetItemRegistry failed, %v\n", err)
+	}
+	if prizepool, err := dapi.GetTournamentPrizePool(9870); err != nil || prizepool.PrizePool == 0 {
+		t.Errorf("GetTournamentPrizePool got %+v, %v\n", prizepool, err)
+	}
+	if leaguegames, err := dapi.GetLiveLeagueGames(); err != nil || len(leaguegames.Leagues) == 0 {
+		t.Errorf("GetLiveLeagueGames got %d games, %v\n", len(leaguegames.Leagues), err)
+	}
+	if toplivegames, err := dapi.GetTopLiveGame(0); err != nil || len(toplivegames.Games) == 0 {
+		t.Errorf("GetTopLiveGame got %d games, %v\n", len(toplivegames.Games), err)
+	}
+
+	if len(srv.Requests()) != 13 {
+		t.Errorf("Server should have received 13 requests, Got:%d\n", len(srv.Requests()))
+	}
+
+	//faults injected by the server go through retry and error handling
+	srv.RateLimit("GetMatchDetails", time.Second, 1)
+	retrying := New(WithBaseURL(srv.URL), WithAPIKey("E09635A9F555CE8F0B0CCEECE8E40434"),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second}))
+	if _, err := retrying.GetMatchDetails("4080856812"); err != nil {
+		t.Errorf("GetMatchDetails should succeed after the 429, Got:%v\n", err)
+	}
+
+	if _, err := New(WithBaseURL(srv.URL), WithAPIKey("wrong")).GetHeroes(""); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Wrong key should fail with ErrUnauthorized, Got:%v\n", err)
+	}
+
+	srv.SetLatency(time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := dapi.GetServerInfoContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Slow server should hit the deadline, Got:%v\n", err)
+	}
+}
@@ -0,0 +1,103 @@
+package dota2
+
+import (
+	"context"
+	"strings"
+)
+
+//sizes of item images on the cdn, use them with ItemRegistry.ImageURL
+const (
+	ITEMIMAGE_LARGE = "lg.png" //85x64
+	ITEMIMAGE_SMALL = "eg.png" //27x20
+
+	ITEM_NAME_PREFIX   = "item_"
+	ITEM_RECIPE_PREFIX = "item_recipe_"
+)
+
+//ShortName strips the "item_" prefix from the internal name, eg: item_blink -> blink.
+func (it Item) ShortName() string {
+	return strings.TrimPrefix(it.Name, ITEM_NAME_PREFIX)
+}
+
+//ImageURL returns the cdn url of the item image, size is one of consts ITEMIMAGE_xx.
+//All recipes share the same scroll image.
+//eg: http://cdn.dota2.com/apps/dota2/images/items/blink_lg.png
+func (it Item) ImageURL(size string) string {
+	name := it.ShortName()
+	if it.Recipe == 1 || strings.HasPrefix(it.Name, ITEM_RECIPE_PREFIX) {
+		name = "recipe"
+	}
+	return BASE_ITEMS_IMAGES_URL + name + "_" + size
+}
+
+//ItemRegistry maps the numeric item id used in item slots(TeamStatistic's Item0..Item5, match details' item_0..item_5)
+//to the item. It's read-only after creation, so it's safe for concurrent use.
+type ItemRegistry struct {
+	items map[int]Item
+}
+
+//NewItemRegistry builds a registry from the result of GetGameItems.
+func NewItemRegistry(items []Item) *ItemRegistry {
+	reg := &ItemRegistry{
+		items: make(map[int]Item, len(items)),
+	}
+	for _, item := range items {
+		reg.items[item.ID] = item
+	}
+	return reg
+}
+
+//GetItemRegistry requests GetGameItems with language and builds an ItemRegistry from the result.
+func (d *Dota2api) GetItemRegistry(language string) (*ItemRegistry, error) {
+	return d.GetItemRegistryContext(context.Background(), language)
+}
+
+//GetItemRegistryContext is like GetItemRegistry but the request is bound to ctx, it's cancelled when ctx is done.
+func (d *Dota2api) GetItemRegistryContext(ctx context.Context, language string) (*ItemRegistry, error) {
+	itemlist, err := d.GetGameItemsContext(ctx, language)
+	if err != nil {
+		return nil, err
+	}
+	return NewItemRegistry(itemlist.Items), nil
+}
+
+//Item looks up the item by id, the bool is false for unknown ids(eg: 0 for an empty slot).
+func (r *ItemRegistry) Item(itemid int) (Item, bool) {
+	item, found := r.items[itemid]
+	return item, found
+}
+
+//Name returns the internal name like item_blink, or "" for unknown ids.
+func (r *ItemRegistry) Name(itemid int) string {
+	return r.items[itemid].Name
+}
+
+//LocalizedName returns the name in the language GetGameItems was called with, or "" for unknown ids.
+func (r *ItemRegistry) LocalizedName(itemid int) string {
+	return r.items[itemid].LocalizedName
+}
+
+//ImageURL returns the cdn url of the item image, or "" for unknown ids.
+func (r *ItemRegistry) ImageURL(itemid int, size string) string {
+	item, found := r.items[itemid]
+	if !found {
+		return ""
+	}
+	return item.ImageURL(size)
+}
+
+//Resolve looks up a list of item slots and keeps their positions, empty or unknown slots become a zero Item.
+//example:
+//	Resolve(int(p.Item0), int(p.Item1), int(p.Item2), int(p.Item3), int(p.Item4), int(p.Item5))
+func (r *ItemRegistry) Resolve(itemids ...int) []Item {
+	items := make([]Item, len(itemids))
+	for i, itemid := range itemids {
+		items[i] = r.items[itemid]
+	}
+	return items
+}
+
+//Len returns the number of items in the registry.
+func (r *ItemRegistry) Len() int {
+	return len(r.items)
+}
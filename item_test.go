@@ -0,0 +1,27 @@
+package dota2
+
+import (
+	"testing"
+)
+
+func TestItemRegistry(t *testing.T) {
+	reg := NewItemRegistry([]Item{
+		{ID: 1, Name: "item_blink", Cost: 2250, SideShop: 1, LocalizedName: "Blink Dagger"},
+		{ID: 37, Name: "item_recipe_ghost", Recipe: 1, LocalizedName: "Ghost Scepter Recipe"},
+		{ID: 46, Name: "item_tpscroll", Cost: 90, LocalizedName: "Town Portal Scroll"},
+	})
+
+	items := reg.Resolve(46, 0, 1, 0, 0, 0)
+	if len(items) != 6 || items[0].Name != "item_tpscroll" || items[1].ID != 0 || items[2].Name != "item_blink" {
+		t.Errorf("Resolve should keep slot positions, Got:%+v\n", items)
+	}
+
+	expected := "http://cdn.dota2.com/apps/dota2/images/items/blink_lg.png"
+	if url := reg.ImageURL(1, ITEMIMAGE_LARGE); url != expected {
+		t.Errorf("Got image url:%s, Expected:%s\n", url, expected)
+	}
+	expected = "http://cdn.dota2.com/apps/dota2/images/items/recipe_lg.png"
+	if url := reg.ImageURL(37, ITEMIMAGE_LARGE); url != expected {
+		t.Errorf("Recipe should use the recipe image, Got:%s, Expected:%s\n", url, expected)
+	}
+}
@@ -0,0 +1,16 @@
+package dota2
+
+//Logger receives the debug events of Dota2api, *slog.Logger satisfies it:
+//	dapi := New(WithLogger(slog.Default()))
+//The api key is always redacted from logged urls. Nothing is logged by default.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+}
+
+//nopLogger is the default Logger, it drops everything.
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, args ...interface{}) {}
+
+func (nopLogger) Warn(msg string, args ...interface{}) {}
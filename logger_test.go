@@ -0,0 +1,34 @@
+package dota2
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":{"leagues":[]}}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	dapi := New(WithBaseURL(srv.URL), WithAPIKey("E09635A9F555CE8F0B0CCEECE8E40434"), WithLogger(logger))
+
+	if _, err := dapi.GetLeagueListing(); err != nil {
+		t.Fatalf("GetLeagueListing failed, %v\n", err)
+	}
+
+	logged := buf.String()
+	if strings.Contains(logged, "E09635A9F555CE8F0B0CCEECE8E40434") {
+		t.Errorf("API key should never be logged, Got:\n%s", logged)
+	}
+	if !strings.Contains(logged, "steam web api request") || !strings.Contains(logged, "endpoint=GetLeagueListing") ||
+		!strings.Contains(logged, "status=200") {
+		t.Errorf("Request and response events should be logged, Got:\n%s", logged)
+	}
+}
@@ -0,0 +1,116 @@
+package dota2
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+//Option configures a Dota2api created by New.
+type Option func(*Dota2api)
+
+//New creates a Dota2api configured by opts, without options it's the same as NewApi(nil).
+//example:
+//	dapi := New(WithAPIKey("AAFB3717E64F8A3C51200A3F7F7988F8"), WithLanguage("zh_cn"), WithRateLimit(1, 1))
+func New(opts ...Option) *Dota2api {
+	dapi := &Dota2api{
+		client:    http.DefaultClient,
+		baseURL:   BASE_URL,
+		logger:    nopLogger{},
+		endpoints: defaultEndpoints(),
+		cacheTTLs: DefaultCacheTTLs(),
+	}
+
+	for _, opt := range opts {
+		opt(dapi)
+	}
+
+	//applied last so that it works no matter whether WithHTTPClient comes before or after it
+	if dapi.timeout > 0 {
+		client := *dapi.client
+		client.Timeout = dapi.timeout
+		dapi.client = &client
+	}
+
+	return dapi
+}
+
+//WithAPIKey sets the Steam Web API key, same as SetApiKey.
+func WithAPIKey(apikey string) Option {
+	return func(d *Dota2api) {
+		d.apikey = apikey
+	}
+}
+
+//WithHTTPClient sends requests with client instead of http.DefaultClient, nil is ignored.
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *Dota2api) {
+		if client != nil {
+			d.client = client
+		}
+	}
+}
+
+//WithBaseURL replaces BASE_URL("http://api.steampowered.com/") of all endpoints,
+//eg: a local Steam Web API stand-in or a proxy.
+func WithBaseURL(baseurl string) Option {
+	return func(d *Dota2api) {
+		if !strings.HasSuffix(baseurl, "/") {
+			baseurl += "/"
+		}
+		d.baseURL = baseurl
+	}
+}
+
+//WithUserAgent sets the User-Agent header of all requests.
+func WithUserAgent(useragent string) Option {
+	return func(d *Dota2api) {
+		d.userAgent = useragent
+	}
+}
+
+//WithLanguage sets the default language of GetHeroes and GetGameItems, used when they're called with "".
+func WithLanguage(language string) Option {
+	return func(d *Dota2api) {
+		d.language = language
+	}
+}
+
+//WithLogger sends request/response debug events of d to logger, eg: slog.Default().
+//nil restores the default, which logs nothing.
+func WithLogger(logger Logger) Option {
+	return func(d *Dota2api) {
+		if logger == nil {
+			logger = nopLogger{}
+		}
+		d.logger = logger
+	}
+}
+
+//WithTimeout sets the timeout of every single request. The client set by WithHTTPClient is copied, not modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(d *Dota2api) {
+		d.timeout = timeout
+	}
+}
+
+//WithRateLimit is the same as SetRateLimit.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(d *Dota2api) {
+		d.SetRateLimit(perSecond, burst)
+	}
+}
+
+//WithDailyBudget is the same as SetDailyBudget.
+func WithDailyBudget(budget int) Option {
+	return func(d *Dota2api) {
+		d.SetDailyBudget(budget)
+	}
+}
+
+//WithRetryPolicy is the same as SetRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(d *Dota2api) {
+		d.SetRetryPolicy(policy)
+	}
+}
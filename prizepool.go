@@ -0,0 +1,96 @@
+package dota2
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	DEFAULT_PRIZEPOOL_INTERVAL = time.Minute
+)
+
+//PrizePoolDelta reports a change of a league's prize pool between two polls.
+//The first poll of every league is reported with Previous=0, so Delta equals the starting pool.
+type PrizePoolDelta struct {
+	LeagueID int
+	Previous int64     //prize pool of the last poll
+	Current  int64     //prize pool of this poll
+	Delta    int64     //Current - Previous
+	Time     time.Time //when this poll was done
+}
+
+//PrizePoolWatcher polls GetTournamentPrizePool for a set of leagues and reports every change.
+type PrizePoolWatcher struct {
+	LeagueIDs []int         //leagues to watch, eg: the LeagueID of GetLeagueListing results
+	Interval  time.Duration //waiting time between two polls, DEFAULT_PRIZEPOOL_INTERVAL when <= 0
+	OnError   func(error)   //optional, called with every failed request, the league is polled again next round
+
+	api  Dota2API
+	last map[int]int64
+}
+
+//NewPrizePoolWatcher creates a watcher polling the prize pools of leagueids through api every DEFAULT_PRIZEPOOL_INTERVAL.
+func NewPrizePoolWatcher(api Dota2API, leagueids ...int) *PrizePoolWatcher {
+	return &PrizePoolWatcher{
+		LeagueIDs: leagueids,
+		Interval:  DEFAULT_PRIZEPOOL_INTERVAL,
+		api:       api,
+	}
+}
+
+//Run polls all leagues every Interval and sends a PrizePoolDelta to the channel whenever a prize pool changed,
+//until ctx is done. The channel is closed when Run returns.
+func (w *PrizePoolWatcher) Run(ctx context.Context, deltas chan<- PrizePoolDelta) error {
+	defer close(deltas)
+
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DEFAULT_PRIZEPOOL_INTERVAL
+	}
+	if w.last == nil {
+		w.last = make(map[int]int64)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, leagueid := range w.LeagueIDs {
+			prizepool, err := w.api.GetTournamentPrizePoolContext(ctx, leagueid)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if w.OnError != nil {
+					w.OnError(err)
+				}
+				continue
+			}
+
+			previous, seen := w.last[leagueid]
+			if seen && previous == prizepool.PrizePool {
+				continue
+			}
+			w.last[leagueid] = prizepool.PrizePool
+
+			delta := PrizePoolDelta{
+				LeagueID: leagueid,
+				Previous: previous,
+				Current:  prizepool.PrizePool,
+				Delta:    prizepool.PrizePool - previous,
+				Time:     time.Now(),
+			}
+			select {
+			case deltas <- delta:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
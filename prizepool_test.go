@@ -0,0 +1,56 @@
+package dota2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPrizePoolWatcher(t *testing.T) {
+	pools := []int64{1600000, 1600000, 1650000}
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pool := pools[len(pools)-1]
+		if polls < len(pools) {
+			pool = pools[polls]
+		}
+		polls++
+		fmt.Fprintf(w, `{"result":{"prize_pool":%d,"league_id":%s,"status":200}}`, pool, r.URL.Query().Get("leagueid"))
+	}))
+	defer srv.Close()
+
+	watcher := NewPrizePoolWatcher(New(WithBaseURL(srv.URL)), 9870)
+	watcher.Interval = time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	deltas := make(chan PrizePoolDelta)
+	go watcher.Run(ctx, deltas)
+
+	first := <-deltas
+	if first.LeagueID != 9870 || first.Previous != 0 || first.Current != 1600000 || first.Delta != 1600000 {
+		t.Errorf("First poll should report the starting pool, Got:%+v\n", first)
+	}
+
+	//the unchanged second poll must be skipped
+	second := <-deltas
+	if second.Previous != 1600000 || second.Current != 1650000 || second.Delta != 50000 {
+		t.Errorf("Got unexpected delta:%+v\n", second)
+	}
+}
+
+func TestPrizePoolWatcherCancel(t *testing.T) {
+	watcher := NewPrizePoolWatcher(NewFake(), 9870, 9871, 9872)
+	watcher.Interval = 0 //falls back to DEFAULT_PRIZEPOOL_INTERVAL instead of panicking
+	watcher.OnError = func(err error) {
+		t.Errorf("OnError shouldn't be called after ctx is done, Got:%v\n", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := watcher.Run(ctx, make(chan PrizePoolDelta)); err != context.Canceled {
+		t.Errorf("Run should return context.Canceled, Got:%v\n", err)
+	}
+}
@@ -0,0 +1,167 @@
+package dota2
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrDailyBudgetExceeded = errors.New("Daily request budget of the api key is exceeded")
+)
+
+//tokenBucket allows perSecond requests on average and bursts of up to burst requests.
+//Tokens may go negative, which is a reservation for callers already waiting.
+type tokenBucket struct {
+	perSecond float64
+	burst     float64
+	tokens    float64
+	last      time.Time
+}
+
+func newTokenBucket(perSecond float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		perSecond: perSecond,
+		burst:     float64(burst),
+		tokens:    float64(burst),
+		last:      time.Now(),
+	}
+}
+
+//reserve takes a token and returns how long the caller has to wait before using it.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.tokens += now.Sub(b.last).Seconds() * b.perSecond
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.perSecond * float64(time.Second))
+}
+
+//rateLimits is the client side throttling of a Dota2api, shared by all its requests.
+type rateLimits struct {
+	mu        sync.Mutex
+	shared    *tokenBucket
+	endpoints map[string]*tokenBucket
+
+	budget    int    //max requests per UTC day, 0 means unlimited
+	used      int    //requests sent in the current day
+	budgetday string //current day, eg: 2018-08-25
+}
+
+//wait blocks until endpoint is allowed to send a request, or ctx is done.
+func (rl *rateLimits) wait(ctx context.Context, endpoint string) error {
+	rl.mu.Lock()
+	if rl.budget > 0 {
+		today := time.Now().UTC().Format("2006-01-02")
+		if today != rl.budgetday {
+			rl.budgetday, rl.used = today, 0
+		}
+		if rl.used >= rl.budget {
+			rl.mu.Unlock()
+			return ErrDailyBudgetExceeded
+		}
+		rl.used++
+	}
+
+	//a request takes a token from its endpoint bucket and from the shared one, as Steam throttles the whole key
+	var buckets []*tokenBucket
+	if bucket, found := rl.endpoints[endpoint]; found {
+		buckets = append(buckets, bucket)
+	}
+	if rl.shared != nil {
+		buckets = append(buckets, rl.shared)
+	}
+
+	var delay time.Duration
+	now := time.Now()
+	for _, bucket := range buckets {
+		if d := bucket.reserve(now); d > delay {
+			delay = d
+		}
+	}
+	rl.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		//give the reserved tokens back, the request is never sent
+		rl.mu.Lock()
+		for _, bucket := range buckets {
+			bucket.tokens++
+		}
+		if rl.budget > 0 {
+			rl.used--
+		}
+		rl.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+//SetRateLimit throttles all requests of d to perSecond requests per second with bursts of up to burst requests,
+//Steam allows roughly one request per second for a key. perSecond <= 0 disables the limit, which is the default.
+//example:
+//	SetRateLimit(1, 1)
+func (d *Dota2api) SetRateLimit(perSecond float64, burst int) {
+	d.limits.mu.Lock()
+	defer d.limits.mu.Unlock()
+
+	d.limits.shared = nil
+	if perSecond > 0 {
+		d.limits.shared = newTokenBucket(perSecond, burst)
+	}
+}
+
+//SetEndpointRateLimit gives endpoint(eg: "GetMatchDetails") its own limit on top of the one set by SetRateLimit,
+//a request waits for both, so an endpoint can be throttled further but never beyond the key-wide limit.
+//perSecond <= 0 removes the endpoint limit.
+func (d *Dota2api) SetEndpointRateLimit(endpoint string, perSecond float64, burst int) {
+	d.limits.mu.Lock()
+	defer d.limits.mu.Unlock()
+
+	if perSecond <= 0 {
+		delete(d.limits.endpoints, endpoint)
+		return
+	}
+	if d.limits.endpoints == nil {
+		d.limits.endpoints = make(map[string]*tokenBucket)
+	}
+	d.limits.endpoints[endpoint] = newTokenBucket(perSecond, burst)
+}
+
+//SetDailyBudget limits the number of requests per UTC day, Steam caps a key at 100000 calls per day.
+//Requests beyond the budget fail with ErrDailyBudgetExceeded. budget <= 0 disables the limit, which is the default.
+func (d *Dota2api) SetDailyBudget(budget int) {
+	d.limits.mu.Lock()
+	defer d.limits.mu.Unlock()
+
+	d.limits.budget = budget
+}
+
+//DailyUsage returns the number of requests sent in the current UTC day and the budget set by SetDailyBudget.
+//Requests are only counted when a budget is set.
+func (d *Dota2api) DailyUsage() (used int, budget int) {
+	d.limits.mu.Lock()
+	defer d.limits.mu.Unlock()
+
+	if d.limits.budgetday != time.Now().UTC().Format("2006-01-02") {
+		return 0, d.limits.budget
+	}
+	return d.limits.used, d.limits.budget
+}
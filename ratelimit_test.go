@@ -0,0 +1,86 @@
+package dota2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	dapi := NewApi(nil)
+	dapi.SetRateLimit(20, 1)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := dapi.RequestForURL(srv.URL); err != nil {
+			t.Fatalf("Request failed, %v\n", err)
+		}
+	}
+	//the first request uses the burst, the next two wait 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 requests at 20/s with burst 1 should take 100ms, took:%v\n", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	dapi.SetRateLimit(0.1, 1)
+	dapi.RequestForURL(srv.URL)
+	if _, err := dapi.RequestForURLContext(ctx, srv.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Waiting for the limit should stop with ctx, Got:%v\n", err)
+	}
+
+	dapi.SetRateLimit(0, 0)
+	dapi.SetDailyBudget(2)
+	for i := 0; i < 2; i++ {
+		if _, err := dapi.RequestForURL(srv.URL); err != nil {
+			t.Fatalf("Request within budget failed, %v\n", err)
+		}
+	}
+	if _, err := dapi.RequestForURL(srv.URL); !errors.Is(err, ErrDailyBudgetExceeded) {
+		t.Errorf("Request beyond budget should fail with ErrDailyBudgetExceeded, Got:%v\n", err)
+	}
+	if used, budget := dapi.DailyUsage(); used != 2 || budget != 2 {
+		t.Errorf("DailyUsage should be 2/2, Got:%d/%d\n", used, budget)
+	}
+}
+
+func TestEndpointRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":{"heroes":[],"status":200}}`))
+	}))
+	defer srv.Close()
+
+	//a generous GetHeroes limit must not push the key past the shared 20/s
+	dapi := New(WithBaseURL(srv.URL))
+	dapi.SetRateLimit(20, 1)
+	dapi.SetEndpointRateLimit("GetHeroes", 1000, 10)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := dapi.GetHeroes("en_us"); err != nil {
+			t.Fatalf("GetHeroes failed, %v\n", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("4 requests should be held to the shared 20/s and take 150ms, took:%v\n", elapsed)
+	}
+
+	//a stricter endpoint limit still applies
+	dapi.SetRateLimit(1000, 10)
+	dapi.SetEndpointRateLimit("GetHeroes", 20, 1)
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		dapi.GetHeroes("en_us")
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 requests at 20/s for GetHeroes should take 100ms, took:%v\n", elapsed)
+	}
+}
@@ -0,0 +1,89 @@
+package dota2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+var (
+	ErrInvalidMatchID   = errors.New("Invalid match id")
+	ErrInvalidSteamID   = errors.New("Invalid steam id")
+	ErrInvalidAccountID = errors.New("Invalid dota2 account id")
+)
+
+//buildURL returns the url of endpoint with the api key and params encoded in the query.
+func (d *Dota2api) buildURL(endpoint string, params url.Values) (string, error) {
+	baseurl, found := d.endpointURL(endpoint)
+	if !found {
+		return "", URLMapError
+	}
+
+	query := url.Values{}
+	if d.apikey != "" {
+		query.Set("key", d.apikey)
+	}
+	for name, values := range params {
+		query[name] = values
+	}
+	if len(query) == 0 {
+		return baseurl, nil
+	}
+	return baseurl + "?" + query.Encode(), nil
+}
+
+//get requests endpoint with params and decodes the json response into v.
+func (d *Dota2api) get(ctx context.Context, endpoint string, params url.Values, v interface{}) error {
+	formurl, err := d.buildURL(endpoint, params)
+	if err != nil {
+		return err
+	}
+
+	ttl, cached := d.cacheTTLs[endpoint]
+	cached = cached && d.cache != nil
+	var key string
+	if cached {
+		key = d.cacheKey(endpoint, params.Encode())
+		if bresp, found := d.cache.Get(key); found {
+			d.logger.Debug("steam web api cache hit", "endpoint", endpoint)
+			return json.Unmarshal(bresp, v)
+		}
+	}
+
+	bresp, err := d.RequestForURLContext(ctx, formurl)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(bresp, v); err != nil {
+		return err
+	}
+
+	//failures reported inside the result are returned along with the decoded result and never cached
+	if err := checkResult(endpoint, bresp); err != nil {
+		return err
+	}
+	if cached {
+		d.cache.Set(key, bresp, ttl)
+	}
+	return nil
+}
+
+//validateID checks that id is a non-negative decimal number fitting in 64 bits,
+//otherwise invalid is returned with id attached.
+func validateID(id string, invalid error) error {
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return fmt.Errorf("%w: %q", invalid, id)
+	}
+	return nil
+}
+
+//validateCount checks a numeric parameter which must be positive, eg: matches_requested.
+func validateCount(name string, count int) error {
+	if count <= 0 {
+		return fmt.Errorf("%w: %s must be greater than 0, got %d", ErrInvalidParameter, name, count)
+	}
+	return nil
+}
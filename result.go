@@ -1,8 +1,16 @@
 package dota2
 
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
 const (
-	PICKBANCOUNT = 22
-	PLAYERCOUNT  = 10
+	TEAM_RADIANT     = 0
+	TEAM_DIRE        = 1
+	TEAM_BROADCASTER = 2
 
 	LOBBYTYPE_INVALID              = -1
 	LOBBYTYPE_PUBLIC_MATCHMAKING   = 0
@@ -54,6 +62,13 @@ const (
 	LEAVERSTATUS_AFK                      = 4
 	LEAVERSTATUS_NEVER_CONNECTED          = 5
 	LEAVERSTATUS_NEVER_CONNECTED_TOO_LONG = 6
+
+	RESULTSTATUS_SUCCESS           = 1   //Success of IDOTA2Match_570 apis
+	RESULTSTATUS_INVALID_PARAMETER = 8   //eg: matches_requested must be greater than 0
+	RESULTSTATUS_ACCESS_DENIED     = 15  //eg: match history of a player who hasn't allowed it
+	RESULTSTATUS_OK                = 200 //Success of IEconDOTA2_570 apis and GetLiveLeagueGames
+
+	RESULTERROR_MATCH_NOT_FOUND = "Match ID not found" //error of GetMatchDetails for unknown or unfinished matches
 )
 
 type MatchHistoryWrapper struct {
@@ -61,11 +76,12 @@ type MatchHistoryWrapper struct {
 }
 
 type MatchHistory struct {
-	Status    int         `json:"status"`
-	ResultNum int         `json:"num_results"`       //Number of matches within a single response
-	TotalNum  int         `json:"total_results"`     //Total number of matches for this query
-	RemainNum int         `json:"results_remaining"` //Number of matches remaining to be retrieved with subsequent API calls
-	Matches   []MatchInfo `json:"matches"`           //Brief Information of a match
+	Status       int         `json:"status"`            //1 -> Success, 15 -> Match history of the player is private
+	StatusDetail string      `json:"statusDetail"`      //Message explaining a failed status
+	ResultNum    int         `json:"num_results"`       //Number of matches within a single response
+	TotalNum     int         `json:"total_results"`     //Total number of matches for this query
+	RemainNum    int         `json:"results_remaining"` //Number of matches remaining to be retrieved with subsequent API calls
+	Matches      []MatchInfo `json:"matches"`           //Brief Information of a match
 }
 
 type MatchInfo struct {
@@ -76,63 +92,163 @@ type MatchInfo struct {
 	RadiantTeamID int   `json:"radiant_team_id"` //Unique Team ID
 	DireTeamID    int   `json:"dire_team_id"`    //Unique Team ID
 	Player        []struct {
-		AccountID  int `json:"account_id"`  //Unique account ID
-		PlayerSlot int `json:"player_slot"` //Player's position within the team
-		HeroID     int `json:"hero_id"`     //Unique hero ID
+		AccountID  int        `json:"account_id"`  //Unique account ID
+		PlayerSlot PlayerSlot `json:"player_slot"` //Player's team and position within the team
+		HeroID     int        `json:"hero_id"`     //Unique hero ID
 	} `json:"players"`
 }
 
 type MatchDetailWrapper struct {
-	Result MatchDetail `json: "result"`
+	Result MatchDetail `json:"result"`
 }
 
 type MatchDetail struct {
-	MatchID               int64           `json:"match_id"`      //Unique match ID
-	MatchSeqNum           int64           `json:"match_seq_num"` //Number indicating position in which this match was recorded
-	RadiantWin            bool            `json:"radiant_win"`   //Win status of game,True for Radiant Win, False for Dire Win
-	PreGameDuration       int             `json:"pre_game_duration"`
-	Duration              int             `json:"duration"`                //Elapsed match time in seconds
-	StartTime             int64           `json:"start_time"`              //Unix timestamp for beginning of match
-	FirstBloodTime        int             `json:"first_blood_time"`        //Time elapsed in seconds since first blood of the match
-	HumanPlayers          int             `json:"human_players"`           //Number of human players in the match
-	LeagueID              int             `json:"leagueid"`                //Unique league ID
-	PostiveVotes          int             `json:"positive_votes"`          //Number of positive/thumbs up votes
-	NegativeVotes         int             `json:"negative_votes"`          //Number of negative/thumbs down votes
-	GameMode              int             `json:"game_mode"`               //match mode, see consts GAMEMODE_xx, eg: 3 -> Random Draft
-	LobbyType             int             `json:"lobby_type"`              //match type, see lobbies.json, eg: 7	-> Ranked matchmaking,天梯匹配
-	RadiantCaptain        int64           `json:"radiant_captain"`         //Account ID for Radiant Captain
-	DireCaptain           int64           `json:"dire_captain"`            //Account ID for Dire Captain
-	TowerStatusRadiant    int             `json:"tower_status_radiant"`    //Status of Radiant Towers
-	TowerStatusDire       int             `json:"tower_status_dire"`       //Status of Dire Towers
-	BarracksStatusRadiant int             `json:"barracks_status_radiant"` //Status of Radiant barracks
-	BarracksStatusDire    int             `json:"barracks_status_dire"`    //Status of Dire barracks
-	Cluster               int             `json:"cluster"`                 //The server cluster the match was played on, used in retrieving replays
-	Engine                int             `json:"engine"`
-	PickBans              PickBanItem     `json:"picks_bans"`
-	Players               PlayerStatistic `json:"players"`
-	RadiantTeamID         int             `json:"radiant_team_id"`       //Radiant Team's unique ID
-	DireTeamID            int             `json:"dire_team_id"`          //Dire Team's unique ID
-	RadiantTeamComplete   int             `json:"radiant_team_complete"` //unknown field...
-	DireTeamComplete      int             `json:"dire_team_complete"`
-	DireName              string          `json:"dire_name"`     //Name of Dire Team
-	RadiantName           string          `json:"radiant_name"`  //Name of Radiant Team
-	Flags                 int             `json:"flags"`         //unknown field...
-	RadiantScore          int             `json:"radiant_score"` //Match score of Radiant team
-	DireScore             int             `json:"dire_score"`
-	DireLogo              int64           `json:"dire_logo"`
-	RadiantLogo           int64           `json:"radiant_logo"`
-}
-
-//PickBanItem is the data type which describes the pick/ban result.
-//total bans -> 6 + 6, total picks -> 5 + 5, so usually 22 pick/ban results were got.
-//map format: map[is_pick:false hero_id:67 team:0 order:0]
-//				is_pick -> false means ban, true means pick
-//				hero_id -> unique hero id
-//				team	-> 0 means Radiant, 1 means Dire, 2 means Broadcaster, 3+ are not assigned
-//				order	-> from 0 to 21, show the order of overall sequence of pick/ban
-type PickBanItem [PICKBANCOUNT]map[string]interface{}
-
-type PlayerStatistic [PLAYERCOUNT]map[string]interface{}
+	MatchID               int64         `json:"match_id"`      //Unique match ID
+	MatchSeqNum           int64         `json:"match_seq_num"` //Number indicating position in which this match was recorded
+	RadiantWin            bool          `json:"radiant_win"`   //Win status of game,True for Radiant Win, False for Dire Win
+	PreGameDuration       int           `json:"pre_game_duration"`
+	Duration              int           `json:"duration"`                //Elapsed match time in seconds
+	StartTime             int64         `json:"start_time"`              //Unix timestamp for beginning of match
+	FirstBloodTime        int           `json:"first_blood_time"`        //Time elapsed in seconds since first blood of the match
+	HumanPlayers          int           `json:"human_players"`           //Number of human players in the match
+	LeagueID              int           `json:"leagueid"`                //Unique league ID
+	PostiveVotes          int           `json:"positive_votes"`          //Number of positive/thumbs up votes
+	NegativeVotes         int           `json:"negative_votes"`          //Number of negative/thumbs down votes
+	GameMode              int           `json:"game_mode"`               //match mode, see consts GAMEMODE_xx, eg: 3 -> Random Draft
+	LobbyType             int           `json:"lobby_type"`              //match type, see lobbies.json, eg: 7	-> Ranked matchmaking,天梯匹配
+	RadiantCaptain        int64         `json:"radiant_captain"`         //Account ID for Radiant Captain
+	DireCaptain           int64         `json:"dire_captain"`            //Account ID for Dire Captain
+	TowerStatusRadiant    int           `json:"tower_status_radiant"`    //Status of Radiant Towers
+	TowerStatusDire       int           `json:"tower_status_dire"`       //Status of Dire Towers
+	BarracksStatusRadiant int           `json:"barracks_status_radiant"` //Status of Radiant barracks
+	BarracksStatusDire    int           `json:"barracks_status_dire"`    //Status of Dire barracks
+	Cluster               int           `json:"cluster"`                 //The server cluster the match was played on, used in retrieving replays
+	Engine                int           `json:"engine"`
+	PickBans              PickBans      `json:"picks_bans"` //Draft of Captains Mode like modes, empty for All Pick
+	Players               []MatchPlayer `json:"players"`
+	RadiantTeamID         int           `json:"radiant_team_id"`       //Radiant Team's unique ID
+	DireTeamID            int           `json:"dire_team_id"`          //Dire Team's unique ID
+	RadiantTeamComplete   int           `json:"radiant_team_complete"` //unknown field...
+	DireTeamComplete      int           `json:"dire_team_complete"`
+	DireName              string        `json:"dire_name"`     //Name of Dire Team
+	RadiantName           string        `json:"radiant_name"`  //Name of Radiant Team
+	Flags                 int           `json:"flags"`         //unknown field...
+	RadiantScore          int           `json:"radiant_score"` //Match score of Radiant team
+	DireScore             int           `json:"dire_score"`
+	DireLogo              int64         `json:"dire_logo"`
+	RadiantLogo           int64         `json:"radiant_logo"`
+}
+
+type MatchHistoryBySeqNumWrapper struct {
+	Result MatchHistoryBySeqNum `json:"result"`
+}
+
+type MatchHistoryBySeqNum struct {
+	Status       int           `json:"status"`       //1 -> Success, 8 -> matches_requested must be greater than 0
+	StatusDetail string        `json:"statusDetail"` //Message explaining a failed status
+	Matches      []MatchDetail `json:"matches"`      //Full match details, ordered by MatchSeqNum
+}
+
+//PickBan is a single pick or ban of the draft.
+type PickBan struct {
+	IsPick bool `json:"is_pick"` //false means ban, true means pick
+	HeroID int  `json:"hero_id"` //Unique hero ID
+	Team   int  `json:"team"`    //See const TEAM_xx
+	Order  int  `json:"order"`   //Position in the overall sequence of picks and bans, from 0
+}
+
+//PickBans is the draft of a match as returned by Steam, its length depends on game mode and patch.
+type PickBans []PickBan
+
+//RadiantPicks returns the heroes picked by Radiant in draft order.
+func (pbs PickBans) RadiantPicks() []PickBan {
+	return pbs.filter(TEAM_RADIANT, true)
+}
+
+//RadiantBans returns the heroes banned by Radiant in draft order.
+func (pbs PickBans) RadiantBans() []PickBan {
+	return pbs.filter(TEAM_RADIANT, false)
+}
+
+//DirePicks returns the heroes picked by Dire in draft order.
+func (pbs PickBans) DirePicks() []PickBan {
+	return pbs.filter(TEAM_DIRE, true)
+}
+
+//DireBans returns the heroes banned by Dire in draft order.
+func (pbs PickBans) DireBans() []PickBan {
+	return pbs.filter(TEAM_DIRE, false)
+}
+
+func (pbs PickBans) filter(team int, ispick bool) []PickBan {
+	var result []PickBan
+	for _, pb := range pbs {
+		if pb.Team == team && pb.IsPick == ispick {
+			result = append(result, pb)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Order < result[j].Order
+	})
+	return result
+}
+
+//MatchPlayer is the statistics of a player at the end of a match.
+type MatchPlayer struct {
+	AccountID         int64            `json:"account_id"`  //32-bit account ID, 4294967295 for anonymous players
+	PlayerSlot        PlayerSlot       `json:"player_slot"` //Player's team and position within the team
+	HeroID            int              `json:"hero_id"`     //Unique hero ID
+	Item0             int              `json:"item_0"`      //Item ID of the top-left inventory slot, 0 for empty
+	Item1             int              `json:"item_1"`
+	Item2             int              `json:"item_2"`
+	Item3             int              `json:"item_3"`
+	Item4             int              `json:"item_4"`
+	Item5             int              `json:"item_5"`
+	Backpack0         int              `json:"backpack_0"` //Item ID of the backpack slots
+	Backpack1         int              `json:"backpack_1"`
+	Backpack2         int              `json:"backpack_2"`
+	ItemNeutral       int              `json:"item_neutral"` //Item ID of the neutral item slot
+	Kills             int              `json:"kills"`
+	Deaths            int              `json:"deaths"`
+	Assists           int              `json:"assists"`
+	LeaverStatus      int              `json:"leaver_status"` //See const LEAVERSTATUS_xx
+	LastHits          int              `json:"last_hits"`
+	Denies            int              `json:"denies"`
+	GoldPerMin        int              `json:"gold_per_min"`
+	XPPerMin          int              `json:"xp_per_min"`
+	Level             int              `json:"level"` //Hero level at the end of the match
+	NetWorth          int              `json:"net_worth"`
+	Gold              int              `json:"gold"` //Unspent gold at the end of the match
+	GoldSpent         int              `json:"gold_spent"`
+	HeroDamage        int              `json:"hero_damage"`
+	TowerDamage       int              `json:"tower_damage"`
+	HeroHealing       int              `json:"hero_healing"`
+	ScaledHeroDamage  int              `json:"scaled_hero_damage"`
+	ScaledTowerDamage int              `json:"scaled_tower_damage"`
+	ScaledHeroHealing int              `json:"scaled_hero_healing"`
+	AbilityUpgrades   []AbilityUpgrade `json:"ability_upgrades"` //Skill build in order
+	AdditionalUnits   []AdditionalUnit `json:"additional_units"` //Controlled units with an inventory, eg: Lone Druid's Spirit Bear
+}
+
+type AbilityUpgrade struct {
+	Ability int `json:"ability"` //Unique ability ID
+	Time    int `json:"time"`    //Elapsed match time in seconds when the ability was learned
+	Level   int `json:"level"`   //Hero level at which the ability was learned
+}
+
+type AdditionalUnit struct {
+	UnitName    string `json:"unitname"` //eg: spirit_bear
+	Item0       int    `json:"item_0"`
+	Item1       int    `json:"item_1"`
+	Item2       int    `json:"item_2"`
+	Item3       int    `json:"item_3"`
+	Item4       int    `json:"item_4"`
+	Item5       int    `json:"item_5"`
+	Backpack0   int    `json:"backpack_0"`
+	Backpack1   int    `json:"backpack_1"`
+	Backpack2   int    `json:"backpack_2"`
+	ItemNeutral int    `json:"item_neutral"`
+}
 
 type LeagueListWrapper struct {
 	League LeagueList `json:"result"`
@@ -207,7 +323,7 @@ type TeamStatistic struct {
 		HeroID uint16 `json:"hero_id"`
 	} `json:"bans"`
 	Players []struct {
-		PlayerSlot       uint8   `json:"player_slot"`
+		PlayerSlot       uint8   `json:"player_slot"` //1-5 within its own team, unlike the PlayerSlot of finished matches
 		AccountID        uint64  `json:"account_id"`
 		HeroID           uint16  `json:"hero_id"`
 		Kills            uint16  `json:"kills"`
@@ -238,6 +354,97 @@ type TeamStatistic struct {
 	} `json:"abilities"`*/
 }
 
+type TeamInfoWrapper struct {
+	Result TeamInfoList `json:"result"`
+}
+
+type TeamInfoList struct {
+	Status       int        `json:"status"`       //1 -> Success, 8 -> teams_requested must be greater than 0
+	StatusDetail string     `json:"statusDetail"` //Message explaining a failed status
+	Teams        []TeamInfo `json:"teams"`
+}
+
+//TeamInfo describes a team registered in Dota2.
+//Valve lists the players as player_0_account_id, player_1_account_id... and the leagues as league_id_0, league_id_1...,
+//they are collected into PlayerAccountIDs and LeagueIDs in the original order.
+type TeamInfo struct {
+	TeamID                    int64   `json:"team_id"`                     //Unique Team ID
+	Name                      string  `json:"name"`                        //Team's name
+	Tag                       string  `json:"tag"`                         //Team's tag, eg: "OG"
+	TimeCreated               int64   `json:"time_created"`                //Unix timestamp of team creation
+	CalibrationGamesRemaining int     `json:"calibration_games_remaining"` //Ranked games left before the team is calibrated
+	Logo                      int64   `json:"logo"`                        //UGC id of the team logo
+	LogoSponsor               int64   `json:"logo_sponsor"`                //UGC id of the team sponsor logo
+	CountryCode               string  `json:"country_code"`                //ISO 3166-1 country code
+	URL                       string  `json:"url"`                         //Team's website
+	GamesPlayed               int     `json:"games_played"`                //Number of games played with the current roster
+	AdminAccountID            int64   `json:"admin_account_id"`            //Account ID of the team admin
+	PlayerAccountIDs          []int64 `json:"-"`                           //Account IDs of the players, from player_0_account_id
+	LeagueIDs                 []int   `json:"-"`                           //IDs of the leagues the team has played in, from league_id_0
+}
+
+//UnmarshalJSON decodes the fixed fields as usual and collects the numbered player/league fields into slices.
+func (ti *TeamInfo) UnmarshalJSON(data []byte) error {
+	type teaminfo TeamInfo //same fields without UnmarshalJSON method, avoids recursion
+	var fixed teaminfo
+	if err := json.Unmarshal(data, &fixed); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	players := make(map[int]int64)
+	leagues := make(map[int]int64)
+	for name, value := range fields {
+		switch {
+		case strings.HasPrefix(name, "player_") && strings.HasSuffix(name, "_account_id"):
+			idx, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "player_"), "_account_id"))
+			if err != nil {
+				continue
+			}
+			var accountid int64
+			if err := json.Unmarshal(value, &accountid); err != nil {
+				return err
+			}
+			players[idx] = accountid
+		case strings.HasPrefix(name, "league_id_"):
+			idx, err := strconv.Atoi(strings.TrimPrefix(name, "league_id_"))
+			if err != nil {
+				continue
+			}
+			var leagueid int64
+			if err := json.Unmarshal(value, &leagueid); err != nil {
+				return err
+			}
+			leagues[idx] = leagueid
+		}
+	}
+
+	*ti = TeamInfo(fixed)
+	ti.PlayerAccountIDs = make([]int64, 0, len(players))
+	for _, idx := range sortedIndexes(players) {
+		ti.PlayerAccountIDs = append(ti.PlayerAccountIDs, players[idx])
+	}
+	ti.LeagueIDs = make([]int, 0, len(leagues))
+	for _, idx := range sortedIndexes(leagues) {
+		ti.LeagueIDs = append(ti.LeagueIDs, int(leagues[idx]))
+	}
+	return nil
+}
+
+//sortedIndexes returns the keys of numbered fields in ascending order.
+func sortedIndexes(fields map[int]int64) []int {
+	idxs := make([]int, 0, len(fields))
+	for idx := range fields {
+		idxs = append(idxs, idx)
+	}
+	sort.Ints(idxs)
+	return idxs
+}
+
 type PlayerSummaryWrapper struct {
 	Response PlayerSummaryList `json:"response"`
 }
@@ -278,3 +485,85 @@ type ServerInfo struct {
 	ServerTime       int64  `json:"servertime"`       // Unix timestamp of WebAPI server.
 	ServerTimeString string `json:"servertimestring"` //time string of WebAPI server.
 }
+
+type HeroListWrapper struct {
+	Result HeroList `json:"result"`
+}
+
+type HeroList struct {
+	Heroes []Hero `json:"heroes"`
+	Status int    `json:"status"` //200 -> Success
+	Count  int    `json:"count"`  //Number of heroes returned
+}
+
+type Hero struct {
+	Name          string `json:"name"`           //Internal name of the hero, eg: npc_dota_hero_antimage
+	ID            int    `json:"id"`             //Unique hero ID
+	LocalizedName string `json:"localized_name"` //Name in the requested language, only present when language is specified
+}
+
+type ItemListWrapper struct {
+	Result ItemList `json:"result"`
+}
+
+type ItemList struct {
+	Items  []Item `json:"items"`
+	Status int    `json:"status"` //200 -> Success
+}
+
+type Item struct {
+	ID            int    `json:"id"`             //Unique item ID
+	Name          string `json:"name"`           //Internal name of the item, eg: item_blink
+	Cost          int    `json:"cost"`           //Gold cost of the item
+	SecretShop    int    `json:"secret_shop"`    //1 if the item is sold in the secret shop
+	SideShop      int    `json:"side_shop"`      //1 if the item is sold in the side shop
+	Recipe        int    `json:"recipe"`         //1 if the item is a recipe
+	LocalizedName string `json:"localized_name"` //Name in the requested language, only present when language is specified
+}
+
+type PrizePoolWrapper struct {
+	Result PrizePool `json:"result"`
+}
+
+type PrizePool struct {
+	PrizePool int64 `json:"prize_pool"` //Current prize pool of the league in USD
+	LeagueID  int   `json:"league_id"`  //Unique league ID
+	Status    int   `json:"status"`     //200 -> Success
+}
+
+//TopLiveGames is returned by GetTopLiveGame directly, there's no "result" wrapper.
+type TopLiveGames struct {
+	Games []TopLiveGame `json:"game_list"`
+}
+
+type TopLiveGame struct {
+	ActivateTime    int64  `json:"activate_time"`     //Unix timestamp of when the game was listed
+	DeactivateTime  int64  `json:"deactivate_time"`   //Unix timestamp of when the game was delisted, 0 if still listed
+	ServerSteamID   uint64 `json:"server_steam_id"`   //Steam ID of the game server
+	LobbyID         uint64 `json:"lobby_id"`          //Unique lobby ID
+	LeagueID        int    `json:"league_id"`         //Unique league ID, 0 for public games
+	LobbyType       int    `json:"lobby_type"`        //See const LOBBYTYPE_xx
+	GameTime        int    `json:"game_time"`         //Elapsed game time in seconds
+	Delay           int    `json:"delay"`             //Broadcast delay in seconds
+	Spectators      int    `json:"spectators"`        //Number of spectators
+	GameMode        int    `json:"game_mode"`         //See const GAMEMODE_xx
+	AverageMMR      int    `json:"average_mmr"`       //Average matchmaking rating of the players
+	MatchID         uint64 `json:"match_id"`          //Unique match ID
+	SeriesID        int    `json:"series_id"`         //Unique series ID, 0 if not part of a series
+	TeamNameRadiant string `json:"team_name_radiant"` //Name of Radiant team, empty for public games
+	TeamNameDire    string `json:"team_name_dire"`    //Name of Dire team, empty for public games
+	TeamLogoRadiant uint64 `json:"team_logo_radiant"` //UGC id of Radiant team logo
+	TeamLogoDire    uint64 `json:"team_logo_dire"`    //UGC id of Dire team logo
+	TeamIDRadiant   int    `json:"team_id_radiant"`   //Radiant Team's unique ID
+	TeamIDDire      int    `json:"team_id_dire"`      //Dire Team's unique ID
+	SortScore       int    `json:"sort_score"`        //Score used by the client to sort top games
+	LastUpdateTime  int64  `json:"last_update_time"`  //Unix timestamp of last update
+	RadiantLead     int    `json:"radiant_lead"`      //Net worth lead of Radiant, negative if Dire leads
+	RadiantScore    int    `json:"radiant_score"`     //Kills of Radiant team
+	DireScore       int    `json:"dire_score"`        //Kills of Dire team
+	Players         []struct {
+		AccountID uint64 `json:"account_id"` //Unique account ID
+		HeroID    int    `json:"hero_id"`    //Unique hero ID, 0 before picking
+	} `json:"players"`
+	BuildingState int64 `json:"building_state"` //Status of both teams' buildings
+}
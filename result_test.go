@@ -0,0 +1,112 @@
+package dota2
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestTeamInfoUnmarshal(t *testing.T) {
+	bteams := []byte(`{"result":{"status":1,"teams":[{"team_id":2586976,"name":"OG","tag":"OG",
+		"time_created":1430400000,"logo":1234,"country_code":"eu","admin_account_id":88,
+		"player_0_account_id":86727555,"player_2_account_id":94155156,"player_1_account_id":101695162,
+		"league_id_1":9870,"league_id_0":5401}]}}`)
+
+	var teaminfowrap TeamInfoWrapper
+	if err := json.Unmarshal(bteams, &teaminfowrap); err != nil {
+		t.Fatalf("Unmarshal TeamInfo failed, %v\n", err)
+	}
+	if len(teaminfowrap.Result.Teams) != 1 {
+		t.Fatalf("Expected 1 team, Got:%d\n", len(teaminfowrap.Result.Teams))
+	}
+
+	team := teaminfowrap.Result.Teams[0]
+	if team.TeamID != 2586976 || team.Name != "OG" || team.Logo != 1234 || team.AdminAccountID != 88 {
+		t.Errorf("Fixed fields not decoded, Got:%+v\n", team)
+	}
+	if fmt.Sprint(team.PlayerAccountIDs) != "[86727555 101695162 94155156]" {
+		t.Errorf("PlayerAccountIDs not in player_N order, Got:%v\n", team.PlayerAccountIDs)
+	}
+	if fmt.Sprint(team.LeagueIDs) != "[5401 9870]" {
+		t.Errorf("LeagueIDs not in league_id_N order, Got:%v\n", team.LeagueIDs)
+	}
+}
+
+func TestMatchPlayerUnmarshal(t *testing.T) {
+	bmatch := []byte(`{"result":{"match_id":4080856812,"players":[{"account_id":86727555,"player_slot":130,
+		"hero_id":80,"item_0":1,"item_5":116,"backpack_1":46,"item_neutral":349,"kills":7,"deaths":2,"assists":11,
+		"leaver_status":0,"last_hits":312,"denies":9,"gold_per_min":612,"xp_per_min":701,"level":25,
+		"net_worth":24530,"hero_damage":21877,"tower_damage":6540,"hero_healing":0,
+		"ability_upgrades":[{"ability":5412,"time":102,"level":1},{"ability":5413,"time":204,"level":2}],
+		"additional_units":[{"unitname":"spirit_bear","item_0":212,"item_neutral":357}]}]}}`)
+
+	var mdwrap MatchDetailWrapper
+	if err := json.Unmarshal(bmatch, &mdwrap); err != nil {
+		t.Fatalf("Unmarshal MatchDetail failed, %v\n", err)
+	}
+	if len(mdwrap.Result.Players) != 1 {
+		t.Fatalf("Expected 1 player, Got:%d\n", len(mdwrap.Result.Players))
+	}
+
+	player := mdwrap.Result.Players[0]
+	if player.AccountID != 86727555 || player.HeroID != 80 || player.Kills != 7 || player.Deaths != 2 ||
+		player.Assists != 11 || player.GoldPerMin != 612 || player.NetWorth != 24530 || player.ItemNeutral != 349 ||
+		player.Item5 != 116 || player.Backpack1 != 46 || player.TowerDamage != 6540 {
+		t.Errorf("Player statistics not decoded, Got:%+v\n", player)
+	}
+	if len(player.AbilityUpgrades) != 2 || player.AbilityUpgrades[1] != (AbilityUpgrade{Ability: 5413, Time: 204, Level: 2}) {
+		t.Errorf("AbilityUpgrades not decoded, Got:%+v\n", player.AbilityUpgrades)
+	}
+	if len(player.AdditionalUnits) != 1 || player.AdditionalUnits[0].UnitName != "spirit_bear" ||
+		player.AdditionalUnits[0].Item0 != 212 {
+		t.Errorf("AdditionalUnits not decoded, Got:%+v\n", player.AdditionalUnits)
+	}
+}
+
+func TestPickBans(t *testing.T) {
+	//a 7.xx Captains Mode draft has 24 entries, the old fixed [22] array dropped the last picks
+	bmatch := []byte(`{"result":{"picks_bans":[` +
+		`{"is_pick":false,"hero_id":67,"team":0,"order":0},{"is_pick":false,"hero_id":41,"team":1,"order":1},` +
+		`{"is_pick":true,"hero_id":8,"team":0,"order":8},{"is_pick":true,"hero_id":80,"team":1,"order":9},` +
+		`{"is_pick":true,"hero_id":74,"team":1,"order":23},{"is_pick":true,"hero_id":2,"team":0,"order":22}]}}`)
+
+	var mdwrap MatchDetailWrapper
+	if err := json.Unmarshal(bmatch, &mdwrap); err != nil {
+		t.Fatalf("Unmarshal MatchDetail failed, %v\n", err)
+	}
+
+	pbs := mdwrap.Result.PickBans
+	if len(pbs) != 6 {
+		t.Fatalf("Expected 6 picks/bans without padding, Got:%d\n", len(pbs))
+	}
+	if pbs[5] != (PickBan{IsPick: true, HeroID: 2, Team: TEAM_RADIANT, Order: 22}) {
+		t.Errorf("Last pick not decoded, Got:%+v\n", pbs[5])
+	}
+
+	heroes := func(list []PickBan) string {
+		var ids []int
+		for _, pb := range list {
+			ids = append(ids, pb.HeroID)
+		}
+		return fmt.Sprint(ids)
+	}
+	if got := heroes(pbs.RadiantPicks()); got != "[8 2]" {
+		t.Errorf("RadiantPicks should be [8 2], Got:%s\n", got)
+	}
+	if got := heroes(pbs.DirePicks()); got != "[80 74]" {
+		t.Errorf("DirePicks should be [80 74], Got:%s\n", got)
+	}
+	if got := heroes(pbs.RadiantBans()); got != "[67]" {
+		t.Errorf("RadiantBans should be [67], Got:%s\n", got)
+	}
+	if got := heroes(pbs.DireBans()); got != "[41]" {
+		t.Errorf("DireBans should be [41], Got:%s\n", got)
+	}
+
+	//All Pick matches have no picks_bans
+	var allpick MatchDetailWrapper
+	json.Unmarshal([]byte(`{"result":{"game_mode":1}}`), &allpick)
+	if len(allpick.Result.PickBans) != 0 || allpick.Result.PickBans.RadiantPicks() != nil {
+		t.Errorf("All Pick match should have no picks/bans, Got:%+v\n", allpick.Result.PickBans)
+	}
+}
@@ -0,0 +1,84 @@
+package dota2
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+//RetryPolicy decides how failed requests are retried. All requests of Dota2api are idempotent GETs,
+//but only transient failures are retried: network errors, empty responses, HTTP 429 and 5xx.
+type RetryPolicy struct {
+	MaxAttempts int           //attempts including the first one, <= 1 disables retrying
+	BaseDelay   time.Duration //delay before the first retry, doubled for every further retry
+	MaxDelay    time.Duration //upper bound of the delay, a longer Retry-After gives up retrying
+}
+
+var (
+	//DefaultRetryPolicy retries twice, after about 0.5s and 1s.
+	DefaultRetryPolicy = RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+	}
+)
+
+//SetRetryPolicy sets how d retries transient failures, retrying is disabled by default.
+//example:
+//	SetRetryPolicy(DefaultRetryPolicy)
+func (d *Dota2api) SetRetryPolicy(policy RetryPolicy) {
+	d.retry = policy
+}
+
+//backoff returns the delay before the next attempt after attempt failed with err,
+//and false if err should not be retried.
+func (p RetryPolicy) backoff(attempt int, err error) (time.Duration, bool) {
+	var (
+		apierr *APIError
+		urlerr *url.Error
+	)
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return 0, false
+	case errors.Is(err, ErrEmptyResponse):
+	case errors.As(err, &apierr):
+		if apierr.StatusCode != http.StatusTooManyRequests && apierr.StatusCode < 500 {
+			return 0, false
+		}
+		if apierr.RetryAfter > 0 {
+			return apierr.RetryAfter, p.MaxDelay <= 0 || apierr.RetryAfter <= p.MaxDelay
+		}
+	case errors.As(err, &urlerr):
+		//network errors of http.Client, the request may not have reached steam at all
+	default:
+		return 0, false
+	}
+
+	delay := p.BaseDelay << uint(attempt-1)
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay <= 0) {
+		delay = p.MaxDelay
+	}
+	//jitter in [delay/2, delay], so parallel clients don't retry in lockstep
+	if half := int64(delay / 2); half > 0 {
+		delay = time.Duration(half + rand.Int63n(half+1))
+	}
+	return delay, true
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,56 @@
+package dota2
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&attempts, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			//200 with an empty body, as GetMatchDetails sometimes does
+		default:
+			w.Write([]byte(`{"servertime":1535126400}`))
+		}
+	}))
+	defer srv.Close()
+
+	dapi := NewApi(nil)
+	dapi.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second})
+
+	start := time.Now()
+	bresp, err := dapi.RequestForURL(srv.URL)
+	if err != nil || string(bresp) != `{"servertime":1535126400}` {
+		t.Fatalf("Request should succeed on the 3rd attempt, Got:%s, %v\n", bresp, err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Retry-After: 1 should delay the retry by 1s, took:%v\n", elapsed)
+	}
+
+	//client errors are never retried
+	atomic.StoreInt32(&attempts, 0)
+	forbidden := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer forbidden.Close()
+	if _, err := dapi.RequestForURL(forbidden.URL); !errors.Is(err, ErrUnauthorized) || atomic.LoadInt32(&attempts) != 1 {
+		t.Errorf("403 should fail without retrying, Got %d attempts, %v\n", atomic.LoadInt32(&attempts), err)
+	}
+
+	//a Retry-After longer than MaxDelay gives up at once
+	dapi.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 100 * time.Millisecond})
+	atomic.StoreInt32(&attempts, 0)
+	if _, err := dapi.RequestForURL(srv.URL); !errors.Is(err, ErrRateLimited) || atomic.LoadInt32(&attempts) != 1 {
+		t.Errorf("Retry-After beyond MaxDelay should not be retried, Got %d attempts, %v\n", atomic.LoadInt32(&attempts), err)
+	}
+}
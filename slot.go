@@ -0,0 +1,54 @@
+package dota2
+
+//PlayerSlot is the player_slot of MatchInfo and MatchDetail players, the high bit tells the team and the low 3 bits
+//the position within it. eg: 0-4 -> Radiant, 128-132 -> Dire
+//The live scoreboard of GetLiveLeagueGames numbers the players 1-5 within each team instead, so it's a plain uint8 there.
+type PlayerSlot uint8
+
+const (
+	PLAYERSLOT_DIRE_BIT      = 0x80
+	PLAYERSLOT_POSITION_MASK = 0x07
+
+	ANONYMOUS_ACCOUNTID = 4294967295 //account_id of every player who hides the match data
+)
+
+func (s PlayerSlot) IsRadiant() bool {
+	return s&PLAYERSLOT_DIRE_BIT == 0
+}
+
+func (s PlayerSlot) IsDire() bool {
+	return s&PLAYERSLOT_DIRE_BIT != 0
+}
+
+//Position returns the position within the team from 0 to 4, it's the order of the lobby and not a role.
+func (s PlayerSlot) Position() int {
+	return int(s & PLAYERSLOT_POSITION_MASK)
+}
+
+//Team returns TEAM_RADIANT or TEAM_DIRE.
+func (s PlayerSlot) Team() int {
+	if s.IsDire() {
+		return TEAM_DIRE
+	}
+	return TEAM_RADIANT
+}
+
+//Won reports whether the team of the slot won a match, radiantWin is MatchDetail.RadiantWin.
+func (s PlayerSlot) Won(radiantWin bool) bool {
+	return s.IsRadiant() == radiantWin
+}
+
+//Won reports whether the player with account won the match, account may be a 32-bit account id as well.
+//played is false when the account isn't among the players(anonymous accounts are never found).
+func (md MatchDetail) Won(account SteamID) (won, played bool) {
+	accountID := account.canonical().AccountID()
+	if accountID == ANONYMOUS_ACCOUNTID {
+		return false, false
+	}
+	for _, player := range md.Players {
+		if player.AccountID == int64(accountID) {
+			return player.PlayerSlot.Won(md.RadiantWin), true
+		}
+	}
+	return false, false
+}
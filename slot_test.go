@@ -0,0 +1,69 @@
+package dota2
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+)
+
+func TestPlayerSlot(t *testing.T) {
+	cases := []struct {
+		slot     PlayerSlot
+		team     int
+		position int
+	}{
+		{0, TEAM_RADIANT, 0},
+		{4, TEAM_RADIANT, 4},
+		{128, TEAM_DIRE, 0},
+		{132, TEAM_DIRE, 4},
+	}
+	for _, c := range cases {
+		if c.slot.Team() != c.team || c.slot.Position() != c.position || c.slot.IsDire() == c.slot.IsRadiant() {
+			t.Errorf("Slot %d should be team %d position %d, Got:team %d position %d\n",
+				c.slot, c.team, c.position, c.slot.Team(), c.slot.Position())
+		}
+	}
+}
+
+func TestMatchDetailWon(t *testing.T) {
+	md := MatchDetail{
+		RadiantWin: false,
+		Players: []MatchPlayer{
+			{AccountID: 86727555, PlayerSlot: 2},
+			{AccountID: 101695162, PlayerSlot: 130},
+			{AccountID: ANONYMOUS_ACCOUNTID, PlayerSlot: 131},
+		},
+	}
+
+	if won, played := md.Won(86727555); won || !played {
+		t.Errorf("Radiant player should have lost, Got:won %v played %v\n", won, played)
+	}
+	if won, played := md.Won(101695162); !won || !played {
+		t.Errorf("Dire player should have won, Got:won %v played %v\n", won, played)
+	}
+	if _, played := md.Won(94155156); played {
+		t.Errorf("94155156 didn't play the match\n")
+	}
+	if won, played := md.Won(ANONYMOUS_ACCOUNTID); won || played {
+		t.Errorf("Anonymous account should never be found, Got:won %v played %v\n", won, played)
+	}
+}
+
+func TestLivePlayerSlot(t *testing.T) {
+	blive, err := ioutil.ReadFile("livinggames.json")
+	if err != nil {
+		t.Fatalf("Read livinggames.json failed, %v\n", err)
+	}
+	var lgwrap LeagueGamesWrapper
+	if err := json.Unmarshal(blive, &lgwrap); err != nil {
+		t.Fatalf("Unmarshal livinggames.json failed, %v\n", err)
+	}
+
+	//live slots count 1-5 within each side of the scoreboard, without the Dire bit
+	dire := lgwrap.LgGames.Leagues[0].ScoreBoard.Dire.Players
+	for i, player := range dire {
+		if int(player.PlayerSlot) != i+1 {
+			t.Errorf("Dire player %d should have live slot %d, Got:%d\n", i, i+1, player.PlayerSlot)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package dota2
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	STEAMID64_BASE = 76561197960265728 //64-bit Steam ID of account id 0: public universe, individual account, desktop instance
+)
+
+//SteamID identifies a Steam account, it's stored in the 64-bit form, eg: 76561198092165728.
+//Dota2 apis like GetMatchHistory use the low 32 bits as account id, eg: 131900000.
+//Dota2api methods also take values up to math.MaxUint32 as account ids, so GetMatchHistory(131900000) works.
+type SteamID uint64
+
+//NewSteamID converts a 32-bit Dota2 account id to a SteamID.
+func NewSteamID(accountID uint32) SteamID {
+	return SteamID(STEAMID64_BASE + uint64(accountID))
+}
+
+//ParseSteamID accepts every common text form of a Steam ID, errors.Is(err, ErrInvalidSteamID) for others:
+//	32-bit account id:	131900000
+//	64-bit Steam ID:	76561198092165728
+//	steam3:			[U:1:131900000]
+//	steam2:			STEAM_0:0:65950000 or STEAM_1:0:65950000
+func ParseSteamID(s string) (SteamID, error) {
+	s = strings.TrimSpace(s)
+	sid, ok := parseSteamID(s)
+	if !ok || !sid.IsValid() {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidSteamID, s)
+	}
+	return sid, nil
+}
+
+func parseSteamID(s string) (SteamID, bool) {
+	switch {
+	case strings.HasPrefix(s, "[U:1:") && strings.HasSuffix(s, "]"):
+		accountID, err := strconv.ParseUint(s[len("[U:1:"):len(s)-1], 10, 32)
+		if err != nil {
+			return 0, false
+		}
+		return NewSteamID(uint32(accountID)), true
+
+	case strings.HasPrefix(s, "STEAM_"):
+		//STEAM_X:Y:Z, X is the universe(0 and 1 both mean public), Y the lowest bit of the account id
+		parts := strings.Split(s[len("STEAM_"):], ":")
+		if len(parts) != 3 || (parts[0] != "0" && parts[0] != "1") || (parts[1] != "0" && parts[1] != "1") {
+			return 0, false
+		}
+		z, err := strconv.ParseUint(parts[2], 10, 31)
+		if err != nil {
+			return 0, false
+		}
+		return NewSteamID(uint32(z<<1) | uint32(parts[1][0]-'0')), true
+	}
+
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return SteamID(id).canonical(), true
+}
+
+//canonical turns a 32-bit account id passed as SteamID into the 64-bit form.
+func (sid SteamID) canonical() SteamID {
+	if sid <= math.MaxUint32 {
+		return NewSteamID(uint32(sid))
+	}
+	return sid
+}
+
+//IsValid reports whether the SteamID is an individual account of the public universe with a non-zero account id.
+func (sid SteamID) IsValid() bool {
+	return uint64(sid)>>32 == STEAMID64_BASE>>32 && sid.AccountID() != 0
+}
+
+//AccountID returns the 32-bit account id used by Dota2, eg: 131900000.
+func (sid SteamID) AccountID() uint32 {
+	return uint32(sid)
+}
+
+//SteamID64 returns the 64-bit form, eg: 76561198092165728.
+func (sid SteamID) SteamID64() uint64 {
+	return uint64(sid)
+}
+
+//String returns the 64-bit form as decimal, which is what the ISteamUser apis expect.
+func (sid SteamID) String() string {
+	return strconv.FormatUint(uint64(sid), 10)
+}
+
+//Steam3 returns the steam3 text form, eg: [U:1:131900000].
+func (sid SteamID) Steam3() string {
+	return "[U:1:" + strconv.FormatUint(uint64(sid.AccountID()), 10) + "]"
+}
+
+//Steam2 returns the steam2 text form with universe 0 as Dota2 and the community use it, eg: STEAM_0:0:65950000.
+func (sid SteamID) Steam2() string {
+	accountID := sid.AccountID()
+	return fmt.Sprintf("STEAM_0:%d:%d", accountID&1, accountID>>1)
+}
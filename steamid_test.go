@@ -0,0 +1,64 @@
+package dota2
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Katsusan/go-dota2/dota2test"
+)
+
+func TestParseSteamID(t *testing.T) {
+	const expected = SteamID(76561198092165728) //account id 131900000
+
+	for _, s := range []string{"131900000", "76561198092165728", "[U:1:131900000]", "STEAM_0:0:65950000", "STEAM_1:0:65950000", " 131900000 "} {
+		sid, err := ParseSteamID(s)
+		if err != nil || sid != expected {
+			t.Errorf("ParseSteamID(%q) should be %d, Got:%d, %v\n", s, expected, sid, err)
+		}
+	}
+
+	for _, s := range []string{"", "131900000d", "-1", "0", "[U:1:0]", "STEAM_0:0:0", "1531490000111111110", "[U:1:abc]", "STEAM_0:2:65950000", "STEAM_0:0"} {
+		if _, err := ParseSteamID(s); !errors.Is(err, ErrInvalidSteamID) {
+			t.Errorf("ParseSteamID(%q) should fail with ErrInvalidSteamID, Got:%v\n", s, err)
+		}
+	}
+
+	if sid, _ := ParseSteamID("STEAM_0:1:43586877"); sid.AccountID() != 87173755 {
+		t.Errorf("Odd account id of steam2 not parsed, Got:%d\n", sid.AccountID())
+	}
+}
+
+func TestSteamIDConversions(t *testing.T) {
+	sid := NewSteamID(131900000)
+	if sid.SteamID64() != 76561198092165728 || sid.AccountID() != 131900000 || !sid.IsValid() {
+		t.Errorf("Unexpected conversion of account id 131900000, Got:%d\n", sid.SteamID64())
+	}
+	if sid.String() != "76561198092165728" || sid.Steam3() != "[U:1:131900000]" || sid.Steam2() != "STEAM_0:0:65950000" {
+		t.Errorf("Unexpected text forms, Got:%s %s %s\n", sid, sid.Steam3(), sid.Steam2())
+	}
+	if SteamID(0).IsValid() || NewSteamID(0).IsValid() || SteamID(131900000).IsValid() {
+		t.Errorf("Zero account ids and 32-bit values should be invalid SteamIDs\n")
+	}
+}
+
+func TestSteamIDAccountIDArgument(t *testing.T) {
+	srv := dota2test.NewServer()
+	defer srv.Close()
+	dapi := New(WithBaseURL(srv.URL))
+
+	//32-bit account ids are accepted wherever a SteamID is expected
+	if _, err := dapi.GetMatchHistory(131900000); err != nil {
+		t.Fatalf("GetMatchHistory with a 32-bit account id failed, %v\n", err)
+	}
+	if _, err := dapi.GetFriendList(131900000, "friend"); err != nil {
+		t.Fatalf("GetFriendList with a 32-bit account id failed, %v\n", err)
+	}
+
+	requests := srv.Requests()
+	if got := requests[0].Query.Get("account_id"); got != "131900000" {
+		t.Errorf("GetMatchHistory should send the 32-bit account id, Got:%s\n", got)
+	}
+	if got := requests[1].Query.Get("steamid"); got != "76561198092165728" {
+		t.Errorf("GetFriendList should send the 64-bit steam id, Got:%s\n", got)
+	}
+}